@@ -201,6 +201,10 @@ func (app *App) Close() error {
 
 // KnownError takes an error and tells us whether it's an error that we know about where we can print a nicely formatted version of it rather than panicking with a stack trace
 func (app *App) KnownError(err error) (string, bool) {
+	if keybindingErr, ok := err.(*gui.KeybindingConfigError); ok {
+		return keybindingErr.Error(), true
+	}
+
 	errorMessage := err.Error()
 
 	mappings := []errorMapping{
@@ -5,9 +5,6 @@ import (
 	"strings"
 
 	"github.com/jesseduffield/lazygit/pkg/theme"
-
-	"github.com/fatih/color"
-	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
 // Branch : A git branch
@@ -22,7 +19,7 @@ type Branch struct {
 
 // GetDisplayStrings returns the display string of branch
 func (b *Branch) GetDisplayStrings(isFocused bool) []string {
-	displayName := utils.ColoredString(b.Name, b.GetColor())
+	displayName := b.GetColor().Sprint(b.Name)
 	if isFocused && b.Selected && b.Pushables != "" && b.Pullables != "" {
 		displayName = fmt.Sprintf("%s ↑%s↓%s", displayName, b.Pushables, b.Pullables)
 	}
@@ -30,18 +27,13 @@ func (b *Branch) GetDisplayStrings(isFocused bool) []string {
 	return []string{b.Recency, displayName}
 }
 
-// GetColor branch color
-func (b *Branch) GetColor() color.Attribute {
-	switch b.getType() {
-	case "feature":
-		return color.FgGreen
-	case "bugfix":
-		return color.FgYellow
-	case "hotfix":
-		return color.FgRed
-	default:
-		return theme.DefaultTextColor
+// GetColor branch color, going by the branch's type as set in the
+// gui.theme.branchColors config
+func (b *Branch) GetColor() theme.TextStyle {
+	if branchColor, ok := theme.BranchColors[b.getType()]; ok {
+		return branchColor
 	}
+	return theme.NewTextStyle(theme.DefaultTextColor)
 }
 
 // expected to return feature/bugfix/hotfix or blank string
@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mgutz/str"
+)
+
+// CmdLogEntry is a command that changed something, as shown in the command log
+type CmdLogEntry struct {
+	Command  string
+	Start    time.Time
+	Duration time.Duration
+	// ExitCode is -1 if the command failed without an exit code, e.g. because
+	// it couldn't be started
+	ExitCode int
+	Output   string
+}
+
+// SetCommandLogger sets the function that's told about every command we run
+// that changes something. It can be called from any goroutine
+func (c *OSCommand) SetCommandLogger(logger func(CmdLogEntry)) {
+	c.commandLogger = logger
+}
+
+// logCommand passes a command that has finished on to the command logger, if
+// it's one that changes something
+func (c *OSCommand) logCommand(command string, start time.Time, output string, err error) {
+	if c.commandLogger == nil || !isMutatingCommand(command) {
+		return
+	}
+	c.commandLogger(CmdLogEntry{
+		Command:  command,
+		Start:    start,
+		Duration: time.Since(start),
+		ExitCode: exitCode(err),
+		Output:   output,
+	})
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitError, ok := err.(*exec.ExitError); ok {
+		return exitError.ExitCode()
+	}
+	return -1
+}
+
+// the git subcommands that only ever read from the repo
+var readOnlyGitCommands = map[string]bool{
+	"blame":        true,
+	"cat-file":     true,
+	"check-ignore": true,
+	"describe":     true,
+	"diff":         true,
+	"diff-files":   true,
+	"diff-index":   true,
+	"diff-tree":    true,
+	"for-each-ref": true,
+	"grep":         true,
+	"help":         true,
+	"log":          true,
+	"ls-files":     true,
+	"ls-remote":    true,
+	"ls-tree":      true,
+	"merge-base":   true,
+	"name-rev":     true,
+	"reflog":       true,
+	"rev-list":     true,
+	"rev-parse":    true,
+	"shortlog":     true,
+	"show":         true,
+	"show-ref":     true,
+	"status":       true,
+	"symbolic-ref": true,
+	"version":      true,
+}
+
+// isMutatingCommand tells us whether a command changes something, going by
+// its git subcommand and arguments. Anything other than git, like a custom
+// command, is assumed to
+func isMutatingCommand(command string) bool {
+	args := str.ToArgv(command)
+	if len(args) == 0 {
+		return false
+	}
+	if filepath.Base(args[0]) != "git" {
+		return true
+	}
+
+	// skip over git's own options, e.g. 'git -c core.pager=cat diff'
+	i := 1
+	for ; i < len(args) && strings.HasPrefix(args[i], "-"); i++ {
+		if args[i] == "-c" || args[i] == "-C" {
+			i++
+		}
+	}
+	if i >= len(args) {
+		return false
+	}
+	subcommand, rest := args[i], args[i+1:]
+	if readOnlyGitCommands[subcommand] {
+		return false
+	}
+
+	flags, positional := splitArgs(rest)
+	switch subcommand {
+	case "branch":
+		return hasAny(flags, "-d", "-D", "--delete", "-m", "-M", "--move", "-c", "-C", "--copy", "-u", "--set-upstream-to", "--unset-upstream", "--edit-description") ||
+			len(positional) > 0 && !hasAny(flags, "-l", "--list", "--contains", "--merged", "--no-merged", "--points-at")
+	case "tag":
+		return hasAny(flags, "-d", "--delete", "-a", "--annotate", "-s", "--sign", "-f", "--force") ||
+			len(positional) > 0 && !hasAny(flags, "-l", "--list", "--contains", "--merged", "--no-merged", "--points-at")
+	case "config":
+		return hasAny(flags, "--add", "--unset", "--unset-all", "--replace-all", "--rename-section", "--remove-section", "-e", "--edit") ||
+			len(positional) > 1 && !hasAny(flags, "--get", "--get-all", "--get-regexp")
+	case "remote":
+		return len(positional) > 0 && positional[0] != "show" && positional[0] != "get-url"
+	case "stash", "worktree":
+		return len(positional) == 0 || positional[0] != "list" && positional[0] != "show"
+	case "interpret-trailers":
+		return hasAny(flags, "--in-place")
+	}
+	return true
+}
+
+// splitArgs splits a command's arguments into its flags and the rest
+func splitArgs(args []string) ([]string, []string) {
+	flags, positional := []string{}, []string{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, strings.SplitN(arg, "=", 2)[0])
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return flags, positional
+}
+
+func hasAny(flags []string, wanted ...string) bool {
+	for _, flag := range flags {
+		for _, w := range wanted {
+			if flag == w {
+				return true
+			}
+		}
+	}
+	return false
+}
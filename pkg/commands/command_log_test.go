@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsMutatingCommand is a function.
+func TestIsMutatingCommand(t *testing.T) {
+	type scenario struct {
+		command  string
+		expected bool
+	}
+
+	scenarios := []scenario{
+		{"git status --untracked-files=all --porcelain", false},
+		{"git -c core.pager=cat diff --color HEAD", false},
+		{"git commit -m 'fix'", true},
+		{"git branch", false},
+		{"git branch -a", false},
+		{"git branch --list 'feature/*'", false},
+		{"git branch new-branch", true},
+		{"git branch -D old-branch", true},
+		{"git config user.email", false},
+		{"git config --get remote.origin.url", false},
+		{"git config user.email me@example.com", true},
+		{"git stash list --pretty='%gs'", false},
+		{"git stash show -p stash@{0}", false},
+		{"git stash --keep-index", true},
+		{"git stash drop stash@{1}", true},
+		{"git remote", false},
+		{"git remote add upstream git@example.com:repo.git", true},
+		{"git interpret-trailers --trailer 'Fixes: #1' msg.txt", false},
+		{"bash -c 'make release'", true},
+		{"git", false},
+		{"", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.command, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, isMutatingCommand(s.command))
+		})
+	}
+}
+
+// TestOSCommandLogCommand is a function.
+func TestOSCommandLogCommand(t *testing.T) {
+	type scenario struct {
+		command string
+		test    func([]CmdLogEntry)
+	}
+
+	scenarios := []scenario{
+		{
+			"echo -n '123'",
+			func(entries []CmdLogEntry) {
+				assert.Len(t, entries, 1)
+				assert.EqualValues(t, "echo -n '123'", entries[0].Command)
+				assert.EqualValues(t, 0, entries[0].ExitCode)
+				assert.EqualValues(t, "123", entries[0].Output)
+			},
+		},
+		{
+			"rmdir unexisting-folder",
+			func(entries []CmdLogEntry) {
+				assert.Len(t, entries, 1)
+				assert.NotEqual(t, 0, entries[0].ExitCode)
+			},
+		},
+		{
+			"git rev-parse --version",
+			func(entries []CmdLogEntry) {
+				assert.Len(t, entries, 0)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.command, func(t *testing.T) {
+			entries := []CmdLogEntry{}
+			osCommand := NewDummyOSCommand()
+			osCommand.SetCommandLogger(func(entry CmdLogEntry) {
+				entries = append(entries, entry)
+			})
+			_, _ = osCommand.RunCommandWithOutput(s.command)
+			s.test(entries)
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"github.com/fatih/color"
 	"github.com/jesseduffield/lazygit/pkg/theme"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 )
@@ -18,42 +17,23 @@ type Commit struct {
 
 // GetDisplayStrings is a function.
 func (c *Commit) GetDisplayStrings(isFocused bool) []string {
-	red := color.New(color.FgRed)
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen)
-	blue := color.New(color.FgBlue)
-	cyan := color.New(color.FgCyan)
-	defaultColor := color.New(theme.DefaultTextColor)
-	magenta := color.New(color.FgMagenta)
+	defaultColor := theme.NewTextStyle(theme.DefaultTextColor)
 
-	// for some reason, setting the background to blue pads out the other commits
-	// horizontally. For the sake of accessibility I'm considering this a feature,
-	// not a bug
-	copied := color.New(color.FgCyan, color.BgBlue)
-
-	var shaColor *color.Color
-	switch c.Status {
-	case "unpushed":
-		shaColor = red
-	case "pushed":
-		shaColor = yellow
-	case "merged":
-		shaColor = green
-	case "rebasing":
-		shaColor = blue
-	case "selected":
-		shaColor = magenta
-	default:
+	shaColor, ok := theme.CommitStatusColors[c.Status]
+	if !ok {
 		shaColor = defaultColor
 	}
 
+	// for some reason, the background (blue by default) pads out the other
+	// commits horizontally. For the sake of accessibility I'm considering this a feature,
+	// not a bug
 	if c.Copied {
-		shaColor = copied
+		shaColor = theme.CherryPickedCommitColor
 	}
 
 	actionString := ""
 	if c.Action != "" {
-		actionString = cyan.Sprint(utils.WithPadding(c.Action, 7)) + " "
+		actionString = theme.RebaseActionColor.Sprint(utils.WithPadding(c.Action, 7)) + " "
 	}
 
 	return []string{shaColor.Sprint(c.Sha), actionString + defaultColor.Sprint(c.Name)}
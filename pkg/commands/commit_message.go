@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jesseduffield/lazygit/pkg/i18n"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// CommitMessageRules are the checks a commit message should pass, as set in
+// the git.commitMessage config
+type CommitMessageRules struct {
+	MaxSubjectLength int
+	SubjectPattern   string
+	NoTrailingPeriod bool
+}
+
+// GetCommitMessageRules returns the commit message rules from the config
+func (c *GitCommand) GetCommitMessageRules() CommitMessageRules {
+	userConfig := c.Config.GetUserConfig()
+	return CommitMessageRules{
+		MaxSubjectLength: userConfig.GetInt("git.commitMessage.maxSubjectLength"),
+		SubjectPattern:   userConfig.GetString("git.commitMessage.subjectPattern"),
+		NoTrailingPeriod: userConfig.GetBool("git.commitMessage.noTrailingPeriod"),
+	}
+}
+
+// CommitMessageViolations returns a description of each of the configured
+// rules that the given message breaks
+func (c *GitCommand) CommitMessageViolations(message string) []string {
+	rules := c.GetCommitMessageRules()
+	lines := strings.Split(message, "\n")
+	subject := lines[0]
+	violations := []string{}
+
+	if rules.MaxSubjectLength > 0 && utf8.RuneCountInString(subject) > rules.MaxSubjectLength {
+		violations = append(violations, c.Tr.TemplateLocalize(
+			"SubjectTooLong",
+			i18n.Teml{
+				"max": rules.MaxSubjectLength,
+			},
+		))
+	}
+	if rules.SubjectPattern != "" {
+		match, err := regexp.MatchString(rules.SubjectPattern, subject)
+		if err != nil {
+			c.Log.Error(err)
+		} else if !match {
+			violations = append(violations, c.Tr.TemplateLocalize(
+				"SubjectDoesNotMatchPattern",
+				i18n.Teml{
+					"pattern": rules.SubjectPattern,
+				},
+			))
+		}
+	}
+	if rules.NoTrailingPeriod && strings.HasSuffix(strings.TrimSpace(subject), ".") {
+		violations = append(violations, c.Tr.SLocalize("SubjectEndsWithPeriod"))
+	}
+
+	return violations
+}
+
+// GetCommitPrefix derives a prefix for a new commit's subject from the branch
+// name, using the git.commitPrefix config. e.g. with a pattern of
+// `^\w+/(\w+-\d+)` and a replacement of `$1: `, the branch
+// feature/PROJ-123-foo gives a prefix of `PROJ-123: `. An empty string is
+// returned if there is no pattern or the branch name doesn't match it
+func (c *GitCommand) GetCommitPrefix(branchName string) string {
+	userConfig := c.Config.GetUserConfig()
+	pattern := userConfig.GetString("git.commitPrefix.pattern")
+	if pattern == "" {
+		return ""
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		c.Log.Error(err)
+		return ""
+	}
+	match := re.FindStringSubmatchIndex(branchName)
+	if match == nil {
+		return ""
+	}
+	return string(re.ExpandString(nil, userConfig.GetString("git.commitPrefix.replace"), branchName, match))
+}
+
+// AddTrailer adds a trailer like `Co-authored-by: Jane <jane@example.com>` to
+// the given commit message, leaving it to git interpret-trailers to decide
+// where it goes and whether an identical trailer is already present
+func (c *GitCommand) AddTrailer(message string, key string, value string) (string, error) {
+	// without a trailing newline git would treat a lone summary line as the
+	// start of the trailers block
+	filename, err := c.OSCommand.CreateTempFile("COMMIT_EDITMSG", message+"\n")
+	if err != nil {
+		c.Log.Error(err)
+		return "", err
+	}
+
+	defer func() { _ = c.OSCommand.Remove(filename) }()
+
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf(
+		"git interpret-trailers --if-exists addIfDifferent --trailer %s %s",
+		c.OSCommand.Quote(fmt.Sprintf("%s: %s", key, value)),
+		c.OSCommand.Quote(filename),
+	))
+	if err != nil {
+		return "", err
+	}
+	// we only trim the end so that an empty summary stays empty
+	return strings.TrimRight(output, " \n"), nil
+}
+
+// GetAuthors returns the authors of the commits reachable from HEAD in the form
+// `Name <email>`, those with the most commits first
+func (c *GitCommand) GetAuthors() ([]string, error) {
+	// without a revision shortlog reads from stdin when it isn't a terminal
+	output, err := c.OSCommand.RunCommandWithOutput("git shortlog -sne HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	authors := []string{}
+	for _, line := range utils.SplitLines(output) {
+		// each line is the commit count followed by a tab and then the author
+		split := strings.SplitN(line, "\t", 2)
+		if len(split) < 2 {
+			continue
+		}
+		authors = append(authors, strings.TrimSpace(split[1]))
+	}
+	return authors, nil
+}
+
+// GetCurrentUserIdentity returns the configured user as `Name <email>`, which
+// is what goes in a Signed-off-by trailer
+func (c *GitCommand) GetCurrentUserIdentity() (string, error) {
+	name, err := c.OSCommand.RunCommandWithOutput("git config user.name")
+	if err != nil {
+		return "", err
+	}
+	email, err := c.OSCommand.RunCommandWithOutput("git config user.email")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s <%s>", strings.TrimSpace(name), strings.TrimSpace(email)), nil
+}
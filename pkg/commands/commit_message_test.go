@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGitCommandCommitMessageViolations is a function.
+func TestGitCommandCommitMessageViolations(t *testing.T) {
+	type scenario struct {
+		testName string
+		config   map[string]interface{}
+		message  string
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"No rules configured",
+			map[string]interface{}{},
+			"A rather long summary that nobody minds.\nsecond line",
+			[]string{},
+		},
+		{
+			"Summary within the max length",
+			map[string]interface{}{"git.commitMessage.maxSubjectLength": 10},
+			"Short one\n\nA description that is much longer than ten characters",
+			[]string{},
+		},
+		{
+			"Summary over the max length",
+			map[string]interface{}{"git.commitMessage.maxSubjectLength": 10},
+			"Not so short",
+			[]string{"summary is longer than 10 characters"},
+		},
+		{
+			"Summary not matching the required pattern",
+			map[string]interface{}{"git.commitMessage.subjectPattern": `^(feat|fix)(\(\w+\))?: `},
+			"update readme",
+			[]string{"summary does not match ^(feat|fix)(\\(\\w+\\))?: "},
+		},
+		{
+			"Summary matching the required pattern",
+			map[string]interface{}{"git.commitMessage.subjectPattern": `^(feat|fix)(\(\w+\))?: `},
+			"fix(gui): handle resizing",
+			[]string{},
+		},
+		{
+			"Trailing period",
+			map[string]interface{}{"git.commitMessage.noTrailingPeriod": true},
+			"Fix the thing.\n\nand another thing",
+			[]string{"summary ends with a period"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			for key, value := range s.config {
+				gitCmd.Config.GetUserConfig().Set(key, value)
+			}
+			assert.EqualValues(t, s.expected, gitCmd.CommitMessageViolations(s.message))
+		})
+	}
+}
+
+// TestGitCommandGetCommitPrefix is a function.
+func TestGitCommandGetCommitPrefix(t *testing.T) {
+	type scenario struct {
+		testName   string
+		pattern    string
+		replace    string
+		branchName string
+		expected   string
+	}
+
+	scenarios := []scenario{
+		{
+			"No pattern configured",
+			"",
+			"",
+			"feature/PROJ-123-foo",
+			"",
+		},
+		{
+			"Branch matches the pattern",
+			`^\w+/(\w+-\d+)`,
+			"$1: ",
+			"feature/PROJ-123-foo",
+			"PROJ-123: ",
+		},
+		{
+			"Branch does not match the pattern",
+			`^\w+/(\w+-\d+)`,
+			"$1: ",
+			"master",
+			"",
+		},
+		{
+			"Invalid pattern",
+			`(`,
+			"$1: ",
+			"feature/PROJ-123-foo",
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.Config.GetUserConfig().Set("git.commitPrefix.pattern", s.pattern)
+			gitCmd.Config.GetUserConfig().Set("git.commitPrefix.replace", s.replace)
+			assert.EqualValues(t, s.expected, gitCmd.GetCommitPrefix(s.branchName))
+		})
+	}
+}
+
+// TestGitCommandAddTrailer is a function.
+func TestGitCommandAddTrailer(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"interpret-trailers", "--if-exists", "addIfDifferent", "--trailer", "Co-authored-by: Jane <jane@example.com>"}, args[0:5])
+		content, err := ioutil.ReadFile(args[5])
+		assert.NoError(t, err)
+		assert.EqualValues(t, "summary\n", string(content))
+
+		return exec.Command("echo", "summary\n\nCo-authored-by: Jane <jane@example.com>\n")
+	}
+
+	message, err := gitCmd.AddTrailer("summary", "Co-authored-by", "Jane <jane@example.com>")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "summary\n\nCo-authored-by: Jane <jane@example.com>", message)
+}
+
+// TestGitCommandGetAuthors is a function.
+func TestGitCommandGetAuthors(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"shortlog", "-sne", "HEAD"}, args)
+
+		return exec.Command("echo", "    12\tJane Doe <jane@example.com>\n     3\tJohn Smith <john@example.com>")
+	}
+
+	authors, err := gitCmd.GetAuthors()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"Jane Doe <jane@example.com>", "John Smith <john@example.com>"}, authors)
+}
@@ -54,5 +54,6 @@ func NewDummyGitCommandWithOSCommand(osCommand *OSCommand) *GitCommand {
 		getGlobalGitConfig: func(string) (string, error) { return "", nil },
 		getLocalGitConfig:  func(string) (string, error) { return "", nil },
 		removeFile:         func(string) error { return nil },
+		status:             &statusState{},
 	}
 }
@@ -5,7 +5,9 @@ package commands
 import (
 	"bufio"
 	"bytes"
+	"io"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/go-errors/errors"
@@ -17,21 +19,32 @@ import (
 // Output is a function that executes by every word that gets read by bufio
 // As return of output you need to give a string that will be written to stdin
 // NOTE: If the return data is empty it won't written anything to stdin
-func RunCommandWithOutputLiveWrapper(c *OSCommand, command string, output func(string) string) error {
+// If stream isn't nil, everything the command writes to stdout and stderr is
+// also copied to it as it comes in
+func RunCommandWithOutputLiveWrapper(c *OSCommand, command string, output func(string) string, stream io.Writer) error {
 	cmd := c.ExecutableFromString(command)
 	cmd.Env = append(cmd.Env, "LANG=en_US.UTF-8", "LC_ALL=en_US.UTF-8")
 
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
+	if stream != nil {
+		cmd.Stderr = io.MultiWriter(&stderr, stream)
+	}
 
+	start := time.Now()
 	ptmx, err := pty.Start(cmd)
 
 	if err != nil {
 		return err
 	}
+	c.trackCommand(cmd)
 
 	go func() {
-		scanner := bufio.NewScanner(ptmx)
+		var reader io.Reader = ptmx
+		if stream != nil {
+			reader = io.TeeReader(ptmx, stream)
+		}
+		scanner := bufio.NewScanner(reader)
 		scanner.Split(scanWordsWithNewLines)
 		for scanner.Scan() {
 			toOutput := strings.Trim(scanner.Text(), " ")
@@ -40,7 +53,11 @@ func RunCommandWithOutputLiveWrapper(c *OSCommand, command string, output func(s
 	}()
 
 	err = cmd.Wait()
+	c.doneWithCommand(cmd)
 	ptmx.Close()
+	// what the command wrote to its terminal is fed to the output function as
+	// it comes in, so we only log what it wrote to stderr
+	c.logCommand(command, start, stderr.String(), err)
 	if err != nil {
 		return errors.New(stderr.String())
 	}
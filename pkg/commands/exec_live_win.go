@@ -2,8 +2,17 @@
 
 package commands
 
+import "io"
+
 // RunCommandWithOutputLiveWrapper runs a command live but because of windows compatibility this command can't be ran there
 // TODO: Remove this hack and replace it with a proper way to run commands live on windows
-func RunCommandWithOutputLiveWrapper(c *OSCommand, command string, output func(string) string) error {
-	return c.RunCommand(command)
+func RunCommandWithOutputLiveWrapper(c *OSCommand, command string, output func(string) string, stream io.Writer) error {
+	if stream == nil {
+		return c.RunCommand(command)
+	}
+	// we can't stream the output here so the best we can do is hand it over
+	// once the command is done
+	commandOutput, err := c.RunCommandWithOutput(command)
+	_, _ = io.WriteString(stream, commandOutput)
+	return err
 }
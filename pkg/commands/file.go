@@ -1,6 +1,6 @@
 package commands
 
-import "github.com/fatih/color"
+import "github.com/jesseduffield/lazygit/pkg/theme"
 
 // File : A file from git status
 // duplicating this for now
@@ -13,16 +13,13 @@ type File struct {
 	HasMergeConflicts       bool
 	HasInlineMergeConflicts bool
 	DisplayString           string
-	Type                    string // one of 'file', 'directory', and 'other'
 	ShortStatus             string // e.g. 'AD', ' A', 'M ', '??'
 }
 
 // GetDisplayStrings returns the display string of a file
 func (f *File) GetDisplayStrings(isFocused bool) []string {
-	// potentially inefficient to be instantiating these color
-	// objects with each render
-	red := color.New(color.FgRed)
-	green := color.New(color.FgGreen)
+	red := theme.UnstagedColor
+	green := theme.StagedColor
 	if !f.Tracked && !f.HasStagedChanges {
 		return []string{red.Sprint(f.DisplayString)}
 	}
@@ -2,10 +2,14 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/mgutz/str"
 
@@ -13,6 +17,7 @@ import (
 
 	"github.com/jesseduffield/lazygit/pkg/config"
 	"github.com/jesseduffield/lazygit/pkg/i18n"
+	"github.com/jesseduffield/lazygit/pkg/theme"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 	"github.com/sirupsen/logrus"
 	gitconfig "github.com/tcnksm/go-gitconfig"
@@ -73,6 +78,20 @@ type GitCommand struct {
 	getLocalGitConfig  func(string) (string, error)
 	removeFile         func(string) error
 	DotGitDir          string
+
+	// DiffOptions are the user's choices for how diffs are shown, e.g. how
+	// much context to include. Left nil, we use git's defaults
+	DiffOptions *config.DiffOptions
+
+	// pagerWidth is the width of the view that paged diffs end up in, which we
+	// hand to the pager so it can lay out side-by-side views and the like.
+	// It's set by the gui as it lays out the views while diffs are loaded in
+	// the background, so it's only accessed atomically
+	pagerWidth int32
+
+	// status is what we keep track of between runs of git status, which is
+	// shared with the copies made by WithTask
+	status *statusState
 }
 
 // NewGitCommand it runs git commands
@@ -116,9 +135,18 @@ func NewGitCommand(log *logrus.Entry, osCommand *OSCommand, tr *i18n.Localizer,
 		getLocalGitConfig:  gitconfig.Local,
 		removeFile:         os.RemoveAll,
 		DotGitDir:          dotGitDir,
+		status:             &statusState{},
 	}, nil
 }
 
+// WithTask returns a copy of the GitCommand whose commands are killed when the
+// task is
+func (c *GitCommand) WithTask(task *Task) *GitCommand {
+	clone := *c
+	clone.OSCommand = c.OSCommand.WithTask(task)
+	return &clone
+}
+
 func findDotGitDir(stat func(string) (os.FileInfo, error), readFile func(filename string) ([]byte, error)) (string, error) {
 	f, err := stat(".git")
 	if err != nil {
@@ -160,12 +188,56 @@ func stashEntryFromLine(line string, index int) *StashEntry {
 
 // GetStashEntryDiff stash diff
 func (c *GitCommand) GetStashEntryDiff(index int) (string, error) {
-	return c.OSCommand.RunCommandWithOutput("git stash show -p --color stash@{" + fmt.Sprint(index) + "}")
+	diff, err := c.OSCommand.RunCommandWithOutput(c.StashEntryDiffCommand(index))
+	if err != nil {
+		return "", err
+	}
+	return c.page(diff), nil
+}
+
+// StashEntryDiffCommand returns the command that shows a stash entry's diff
+func (c *GitCommand) StashEntryDiffCommand(index int) string {
+	return "git stash show -p " + c.colorArg() + c.diffArgs(false) + " stash@{" + fmt.Sprint(index) + "}"
+}
+
+// the ways of showing untracked files that git.untrackedFiles can be set to
+const (
+	// UntrackedFilesAll lists untracked files along with the rest
+	UntrackedFilesAll = "all"
+	// UntrackedFilesLazy lists the tracked files first and adds the untracked
+	// ones once we've scanned the working tree for them
+	UntrackedFilesLazy = "lazy"
+	// UntrackedFilesHidden leaves untracked files out altogether
+	UntrackedFilesHidden = "hidden"
+)
+
+// UntrackedFilesMode returns how the user wants untracked files shown. Finding
+// them means walking the whole working tree, which is slow in a big repo
+func (c *GitCommand) UntrackedFilesMode() string {
+	switch mode := c.Config.GetUserConfig().GetString("git.untrackedFiles"); mode {
+	case UntrackedFilesLazy, UntrackedFilesHidden:
+		return mode
+	default:
+		return UntrackedFilesAll
+	}
 }
 
 // GetStatusFiles git status files
 func (c *GitCommand) GetStatusFiles() []*File {
-	statusOutput, _ := c.GitStatus()
+	if c.UntrackedFilesMode() == UntrackedFilesHidden {
+		return c.GetTrackedStatusFiles()
+	}
+	return c.getStatusFiles("all")
+}
+
+// GetTrackedStatusFiles is GetStatusFiles without the untracked files, which
+// doesn't need git to walk the working tree
+func (c *GitCommand) GetTrackedStatusFiles() []*File {
+	return c.getStatusFiles("no")
+}
+
+func (c *GitCommand) getStatusFiles(untrackedFiles string) []*File {
+	statusOutput, _ := c.GitStatus(untrackedFiles)
 	statusStrings := utils.SplitLines(statusOutput)
 	files := []*File{}
 
@@ -188,7 +260,6 @@ func (c *GitCommand) GetStatusFiles() []*File {
 			Deleted:                 unstagedChange == "D" || stagedChange == "D",
 			HasMergeConflicts:       hasMergeConflicts,
 			HasInlineMergeConflicts: hasInlineMergeConflicts,
-			Type:                    c.OSCommand.FileType(filename),
 			ShortStatus:             change,
 		}
 		files = append(files, file)
@@ -196,6 +267,35 @@ func (c *GitCommand) GetStatusFiles() []*File {
 	return files
 }
 
+// IgnoredDirectories returns the directories in the working tree that git
+// ignores as a whole, e.g. because of a .gitignore file
+func (c *GitCommand) IgnoredDirectories() ([]string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git ls-files -z --others --ignored --exclude-standard --directory")
+	if err != nil {
+		return nil, err
+	}
+	directories := []string{}
+	for _, path := range strings.Split(output, "\x00") {
+		if strings.HasSuffix(path, "/") {
+			directories = append(directories, strings.TrimSuffix(path, "/"))
+		}
+	}
+	return directories, nil
+}
+
+// IgnoredPaths returns those of the given paths that git ignores
+func (c *GitCommand) IgnoredPaths(paths []string) []string {
+	// git check-ignore fails when none of the paths are ignored
+	output, _ := c.OSCommand.RunDirectCommandWithInput("git check-ignore -z --stdin", strings.Join(paths, "\x00"))
+	ignored := []string{}
+	for _, path := range strings.Split(output, "\x00") {
+		if path != "" {
+			ignored = append(ignored, path)
+		}
+	}
+	return ignored
+}
+
 // StashDo modify stash
 func (c *GitCommand) StashDo(index int, method string) error {
 	return c.OSCommand.RunCommand(fmt.Sprintf("git stash %s stash@{%d}", method, index))
@@ -260,8 +360,36 @@ func (c *GitCommand) GetCurrentBranchUpstreamDifferenceCount() (string, string)
 }
 
 func (c *GitCommand) GetBranchUpstreamDifferenceCount(branchName string) (string, string) {
-	upstream := "origin" // hardcoded for now
-	return c.GetCommitDifferences(branchName, fmt.Sprintf("%s/%s", upstream, branchName))
+	return c.GetCommitDifferences(branchName, branchName+"@{u}")
+}
+
+// GetUpstream returns the remote and remote branch name that the given branch
+// tracks, according to its branch.<name>.remote and branch.<name>.merge config.
+// An error is returned if the branch has no upstream
+func (c *GitCommand) GetUpstream(branchName string) (string, string, error) {
+	remote, err := c.getLocalGitConfig(fmt.Sprintf("branch.%s.remote", branchName))
+	if err != nil {
+		return "", "", err
+	}
+	merge, err := c.getLocalGitConfig(fmt.Sprintf("branch.%s.merge", branchName))
+	if err != nil {
+		return "", "", err
+	}
+	remote = strings.TrimSpace(remote)
+	merge = strings.TrimSpace(merge)
+	if remote == "" || merge == "" {
+		return "", "", errors.New(c.Tr.SLocalize("NoUpstreamForBranch"))
+	}
+	return remote, strings.TrimPrefix(merge, "refs/heads/"), nil
+}
+
+// GetRemotes returns the names of the configured remotes
+func (c *GitCommand) GetRemotes() ([]string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git remote")
+	if err != nil {
+		return nil, err
+	}
+	return utils.SplitLines(output), nil
 }
 
 // GetCommitDifferences checks how many pushables/pullables there are for the
@@ -342,9 +470,20 @@ func (c *GitCommand) ListStash() (string, error) {
 	return c.OSCommand.RunCommandWithOutput("git stash list")
 }
 
-// Merge merge
-func (c *GitCommand) Merge(branchName string) error {
-	return c.OSCommand.RunCommand(fmt.Sprintf("git merge --no-edit %s", branchName))
+// Merge merges the given branch into the checked out branch. mergeFlag can be
+// one of "", "--no-ff", "--ff-only" or "--squash". If message is not blank it
+// will be used as the merge commit's message instead of git's default one
+func (c *GitCommand) Merge(branchName string, mergeFlag string, message string) error {
+	command := "git merge --no-edit"
+	if mergeFlag != "" {
+		command = fmt.Sprintf("%s %s", command, mergeFlag)
+	}
+	// a squash merge doesn't create a commit so there is no message to set
+	if message != "" && mergeFlag != "--squash" {
+		command = fmt.Sprintf("%s -m %s", command, c.OSCommand.Quote(message))
+	}
+
+	return c.OSCommand.RunCommand(fmt.Sprintf("%s %s", command, branchName))
 }
 
 // AbortMerge abort merge
@@ -364,16 +503,95 @@ func (c *GitCommand) usingGpg() bool {
 	return value == "true" || value == "1" || value == "yes" || value == "on"
 }
 
-// Commit commits to git
-func (c *GitCommand) Commit(message string, flags string) (*exec.Cmd, error) {
+// Commit commits to git. If stream isn't nil, the output of any hooks is copied
+// to it while they run
+func (c *GitCommand) Commit(message string, flags string, stream io.Writer) (*exec.Cmd, error) {
 	command := fmt.Sprintf("git commit %s -m %s", flags, c.OSCommand.Quote(message))
 	if c.usingGpg() {
 		return c.OSCommand.PrepareSubProcess(c.OSCommand.Platform.shell, c.OSCommand.Platform.shellArg, command), nil
 	}
 
+	if stream != nil {
+		return nil, c.OSCommand.RunCommandWithStream(command, stream)
+	}
 	return nil, c.OSCommand.RunCommand(command)
 }
 
+// HasHook tells us whether the repo has a hook of the given name, e.g.
+// pre-commit, looking in core.hooksPath if that's been set
+func (c *GitCommand) HasHook(name string) bool {
+	hooksPath := c.getConfigPath("core.hooksPath")
+	if hooksPath == "" {
+		hooksPath = filepath.Join(c.DotGitDir, "hooks")
+	}
+	fileInfo, err := os.Stat(filepath.Join(hooksPath, name))
+	if err != nil || fileInfo.IsDir() {
+		return false
+	}
+	// git ignores hooks that aren't executable, except on windows where there's
+	// no such thing
+	return c.OSCommand.Platform.os == "windows" || fileInfo.Mode()&0111 != 0
+}
+
+// GetCommitMessage returns the full message of the given commit
+func (c *GitCommand) GetCommitMessage(sha string) (string, error) {
+	message, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log -1 --format=%%B %s", sha))
+	return strings.TrimSpace(message), err
+}
+
+// GetMergeMessage returns the message git has prepared for the merge commit
+// while we're in the middle of a merge, or an empty string if there isn't one
+func (c *GitCommand) GetMergeMessage() string {
+	content, err := ioutil.ReadFile(filepath.Join(c.DotGitDir, "MERGE_MSG"))
+	if err != nil {
+		return ""
+	}
+	return stripCommentLines(string(content))
+}
+
+// GetCommitTemplate returns the content of the file configured with
+// commit.template, or an empty string if there is no template
+func (c *GitCommand) GetCommitTemplate() string {
+	path := c.getConfigPath("commit.template")
+	if path == "" {
+		return ""
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		c.Log.Error(err)
+		return ""
+	}
+	return stripCommentLines(string(content))
+}
+
+// getConfigPath returns the path held in the given git config key, looking in
+// the local config before the global one, with a leading ~/ expanded as git
+// would
+func (c *GitCommand) getConfigPath(key string) string {
+	path, _ := c.getLocalGitConfig(key)
+	if path == "" {
+		path, _ = c.getGlobalGitConfig(key)
+	}
+	if strings.HasPrefix(path, "~/") {
+		path = filepath.Join(c.OSCommand.getenv("HOME"), path[2:])
+	}
+	return path
+}
+
+// stripCommentLines removes the lines that git would treat as comments when
+// cleaning up a commit message in an editor. We pass messages with -m, where
+// git leaves comments alone, so we need to do this ourselves
+func stripCommentLines(message string) string {
+	lines := []string{}
+	for _, line := range strings.Split(utils.NormalizeLinefeeds(message), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
 // AmendHead amends HEAD with whatever is staged in your working tree
 func (c *GitCommand) AmendHead() (*exec.Cmd, error) {
 	command := "git commit --amend --no-edit"
@@ -384,20 +602,66 @@ func (c *GitCommand) AmendHead() (*exec.Cmd, error) {
 	return nil, c.OSCommand.RunCommand(command)
 }
 
-// Pull pulls from repo
-func (c *GitCommand) Pull(ask func(string) string) error {
-	return c.OSCommand.DetectUnamePass("git pull --no-edit", ask)
-}
+// the ways in which the checked out branch can be reconciled with its upstream
+// when pulling
+const (
+	PullModeMerge           = "merge"
+	PullModeRebase          = "rebase"
+	PullModeFastForwardOnly = "ff-only"
+)
 
-// Push pushes to a branch
-func (c *GitCommand) Push(branchName string, force bool, ask func(string) string) error {
-	forceFlag := ""
-	if force {
-		forceFlag = "--force-with-lease "
+// ReconcileWithUpstream brings the checked out branch up to date with its
+// already-fetched upstream, either by merging it in, rebasing onto it, or only
+// fast-forwarding to it
+func (c *GitCommand) ReconcileWithUpstream(mode string) error {
+	switch mode {
+	case PullModeRebase:
+		return c.RebaseBranch("@{u}")
+	case PullModeFastForwardOnly:
+		return c.Merge("@{u}", "--ff-only", "")
+	default:
+		return c.Merge("@{u}", "", "")
+	}
+}
+
+// PushOpts holds the options for a push. If Remote is blank, git decides
+// where to push based on the branch's upstream and the push.default config.
+// RemoteBranch defaults to the name of the local branch
+type PushOpts struct {
+	Force        bool
+	NoVerify     bool
+	Tags         bool
+	SetUpstream  bool
+	Remote       string
+	RemoteBranch string
+}
+
+// Push pushes to a branch. If stream isn't nil, the push's output, including
+// that of any pre-push hook, is copied to it as it comes in
+func (c *GitCommand) Push(branchName string, opts PushOpts, ask func(string) string, stream io.Writer) error {
+	cmd := "git push"
+	if opts.Force {
+		cmd += " --force-with-lease"
+	}
+	if opts.NoVerify {
+		cmd += " --no-verify"
+	}
+	if opts.Tags {
+		cmd += " --follow-tags"
+	}
+
+	if opts.Remote != "" {
+		if opts.SetUpstream {
+			cmd += " -u"
+		}
+		refspec := branchName
+		if opts.RemoteBranch != "" && opts.RemoteBranch != branchName {
+			refspec = fmt.Sprintf("%s:%s", branchName, opts.RemoteBranch)
+		}
+		cmd = fmt.Sprintf("%s %s %s", cmd, opts.Remote, refspec)
 	}
 
-	cmd := fmt.Sprintf("git push %s-u origin %s", forceFlag, branchName)
-	return c.OSCommand.DetectUnamePass(cmd, ask)
+	return c.OSCommand.DetectUnamePassWithStream(cmd, ask, stream)
 }
 
 // CatFile obtains the content of a file
@@ -437,18 +701,57 @@ func (c *GitCommand) UnStageFile(fileName string, tracked bool) error {
 	return nil
 }
 
-// GitStatus returns the plaintext short status of the repo
-func (c *GitCommand) GitStatus() (string, error) {
-	return c.OSCommand.RunCommandWithOutput("git status --untracked-files=all --porcelain")
+// GitStatus returns the plaintext short status of the repo, where
+// untrackedFiles is 'all' or 'no', as passed to --untracked-files
+func (c *GitCommand) GitStatus(untrackedFiles string) (string, error) {
+	cmd := c.OSCommand.ExecutableFromString("git status --untracked-files=" + untrackedFiles + " --porcelain")
+	if c.usingStatusCaches() {
+		// we normally stop git from taking the index lock while we look at
+		// the repo, but then git status can't save the caches it's updated
+		// and has to start from scratch every time
+		cmd.Env = os.Environ()
+		// once it's done writing to the index we take note of it, so that
+		// we don't mistake its changes for anybody else's
+		defer c.recordStatusIndexStamp()
+	} else {
+		c.recordStatusIndexStamp()
+	}
+	return c.OSCommand.RunExecutableWithOutput(cmd)
 }
 
-// IsInMergeState states whether we are still mid-merge
+// usingStatusCaches tells us whether the user has turned on the untracked cache
+// or a file system monitor, both of which git keeps in the index. We only look
+// the first time, as reading the config costs us as much as we're trying to save
+func (c *GitCommand) usingStatusCaches() bool {
+	c.status.cachesOnce.Do(func() {
+		for _, key := range []string{"core.untrackedCache", "core.fsmonitor"} {
+			value, _ := c.getLocalGitConfig(key)
+			if value == "" {
+				value, _ = c.getGlobalGitConfig(key)
+			}
+			switch strings.ToLower(strings.TrimSpace(value)) {
+			case "", "false", "no", "off", "0":
+			default:
+				c.status.caches = true
+			}
+		}
+	})
+	return c.status.caches
+}
+
+// IsInMergeState states whether we are still mid-merge, i.e. we have a merge
+// to conclude or there are unmerged paths
 func (c *GitCommand) IsInMergeState() (bool, error) {
-	output, err := c.OSCommand.RunCommandWithOutput("git status --untracked-files=all")
+	merging, err := c.OSCommand.FileExists(filepath.Join(c.DotGitDir, "MERGE_HEAD"))
+	if err != nil || merging {
+		return merging, err
+	}
+	// unlike git status this only has to look at the index
+	output, err := c.OSCommand.RunCommandWithOutput("git ls-files --unmerged")
 	if err != nil {
 		return false, err
 	}
-	return strings.Contains(output, "conclude merge") || strings.Contains(output, "unmerged paths"), nil
+	return strings.TrimSpace(output) != "", nil
 }
 
 // RebaseMode returns "" for non-rebase mode, "normal" for normal rebase
@@ -520,7 +823,7 @@ func (c *GitCommand) PrepareCommitAmendSubProcess() *exec.Cmd {
 // Currently it limits the result to 100 commits, but when we get async stuff
 // working we can do lazy loading
 func (c *GitCommand) GetBranchGraph(branchName string) (string, error) {
-	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log --graph --color --abbrev-commit --decorate --date=relative --pretty=medium -100 %s", branchName))
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log --graph %s --abbrev-commit --decorate --date=relative --pretty=medium -100 %s", c.colorArg(), branchName))
 }
 
 // Ignore adds a file to the gitignore for the repo
@@ -530,7 +833,7 @@ func (c *GitCommand) Ignore(filename string) error {
 
 // Show shows the diff of a commit
 func (c *GitCommand) Show(sha string) (string, error) {
-	show, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show --color %s", sha))
+	show, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show %s%s %s", c.colorArg(), c.diffArgs(false), sha))
 	if err != nil {
 		return "", err
 	}
@@ -539,10 +842,10 @@ func (c *GitCommand) Show(sha string) (string, error) {
 	revList, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git rev-list -1 --merges %s^...%s", sha, sha))
 	if err != nil {
 		// turns out we get an error here when it's the first commit. We'll just return the original show
-		return show, nil
+		return c.page(show), nil
 	}
 	if len(revList) == 0 {
-		return show, nil
+		return c.page(show), nil
 	}
 
 	// we want to pull out 1a6a69a and 3b51d7c from this:
@@ -550,19 +853,39 @@ func (c *GitCommand) Show(sha string) (string, error) {
 	// Merge: 1a6a69a 3b51d7c
 	lines := utils.SplitLines(show)
 	if len(lines) < 2 {
-		return show, nil
+		return c.page(show), nil
 	}
 
 	secondLineWords := strings.Split(lines[1], " ")
 	if len(secondLineWords) < 3 {
-		return show, nil
+		return c.page(show), nil
 	}
 
-	mergeDiff, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git diff --color %s...%s", secondLineWords[1], secondLineWords[2]))
+	mergeDiff, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git diff %s%s %s...%s", c.colorArg(), c.diffArgs(false), secondLineWords[1], secondLineWords[2]))
 	if err != nil {
 		return "", err
 	}
-	return show + mergeDiff, nil
+	return c.page(show + mergeDiff), nil
+}
+
+// ShowCommands returns the commands whose output, one after the other, makes up
+// what Show shows for a commit, for when we want to stream it rather than
+// wait for all of it. A merge commit is followed by the diff between the two
+// branches it merged
+func (c *GitCommand) ShowCommands(sha string) []string {
+	commands := []string{fmt.Sprintf("git show %s%s %s", c.colorArg(), c.diffArgs(false), sha)}
+
+	// this gives us the commit followed by its parents, and fails for the
+	// first commit, which is no merge anyway
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git rev-list --parents -n 1 %s", sha))
+	if err != nil {
+		return commands
+	}
+	shas := strings.Fields(output)
+	if len(shas) < 3 {
+		return commands
+	}
+	return append(commands, fmt.Sprintf("git diff %s%s %s...%s", c.colorArg(), c.diffArgs(false), shas[1], shas[2]))
 }
 
 // GetRemoteURL returns current repo remote url
@@ -581,11 +904,107 @@ func (c *GitCommand) CheckRemoteBranchExists(branch *Branch) bool {
 	return err == nil
 }
 
-// Diff returns the diff of a file
-func (c *GitCommand) Diff(file *File, plain bool) string {
+// colorArg returns the flag that colors a command's output, or turns color off
+// when the user has set NO_COLOR
+func (c *GitCommand) colorArg() string {
+	if theme.NoColor {
+		return "--no-color"
+	}
+	return "--color"
+}
+
+// SetPagerWidth sets the width we tell the pager it has to work with
+func (c *GitCommand) SetPagerWidth(width int) {
+	atomic.StoreInt32(&c.pagerWidth, int32(width))
+}
+
+// UsingPager tells us whether diffs go through the user's pager before we show
+// them, in which case we need the whole diff at once
+func (c *GitCommand) UsingPager() bool {
+	return c.Config.GetUserConfig().GetString("git.paging.pager") != ""
+}
+
+// page pipes a diff through the pager set in git.paging.pager (e.g. delta),
+// returning the diff untouched if there is no pager or it fails to run. The
+// pager is told the width it has to work with both through COLUMNS and the
+// {{columnWidth}} placeholder, seeing as it isn't writing to a terminal
+func (c *GitCommand) page(diff string) string {
+	pager := c.Config.GetUserConfig().GetString("git.paging.pager")
+	if pager == "" || diff == "" {
+		return diff
+	}
+
+	width := strconv.Itoa(int(atomic.LoadInt32(&c.pagerWidth)))
+	command := utils.ResolvePlaceholderString(pager, map[string]string{
+		"columnWidth": width,
+	})
+	output, err := c.OSCommand.RunDirectCommandWithInput(command, diff, "COLUMNS="+width)
+	if err != nil {
+		c.Log.Error(err)
+		return diff
+	}
+	return output
+}
+
+// diffArgs returns the arguments for the user's diff options, each with a
+// leading space. Diffs for the staging panel end up as patches that git has to
+// apply, so they only get the context size, and at least one line of it: git
+// apply can't place hunks without context, and whitespace-blind or word diffs
+// don't apply at all
+func (c *GitCommand) diffArgs(forStaging bool) string {
+	options := c.DiffOptions
+	if options == nil {
+		return ""
+	}
+
+	args := ""
+	contextLines := options.ContextLines
+	if forStaging && contextLines < 1 {
+		contextLines = 1
+	}
+	if contextLines != config.DefaultDiffContextLines {
+		args += fmt.Sprintf(" -U%d", contextLines)
+	}
+	if forStaging {
+		return args
+	}
+
+	if options.IgnoreWhitespace {
+		args += " -w"
+	}
+	if options.WordDiff {
+		if theme.NoColor {
+			args += " --word-diff=plain"
+		} else {
+			args += " --word-diff=color"
+		}
+	}
+	if options.RenameThreshold > 0 {
+		args += fmt.Sprintf(" -M%d%%", options.RenameThreshold)
+	}
+	if options.CopyThreshold > 0 {
+		args += fmt.Sprintf(" -C%d%%", options.CopyThreshold)
+	}
+	return args
+}
+
+// Diff returns the diff of a file. The staging panel needs git's own diff so
+// that its lines match up with the plain diff and can be turned back into a
+// patch, so forStaging skips the user's pager and most of their diff options
+func (c *GitCommand) Diff(file *File, plain bool, forStaging bool) string {
+	// for now we assume an error means the file was deleted
+	s, _ := c.OSCommand.RunCommandWithOutput(c.DiffCommand(file, plain, forStaging))
+	if !forStaging && !plain {
+		return c.page(s)
+	}
+	return s
+}
+
+// DiffCommand returns the command that Diff runs to get a file's diff
+func (c *GitCommand) DiffCommand(file *File, plain bool, forStaging bool) string {
 	cachedArg := ""
 	trackedArg := "--"
-	colorArg := "--color"
+	colorArg := c.colorArg()
 	split := strings.Split(file.Name, " -> ") // in case of a renamed file we get the new filename
 	fileName := c.OSCommand.Quote(split[len(split)-1])
 	if file.HasStagedChanges && !file.HasUnstagedChanges {
@@ -598,11 +1017,7 @@ func (c *GitCommand) Diff(file *File, plain bool) string {
 		colorArg = ""
 	}
 
-	command := fmt.Sprintf("git diff %s %s %s %s", colorArg, cachedArg, trackedArg, fileName)
-
-	// for now we assume an error means the file was deleted
-	s, _ := c.OSCommand.RunCommandWithOutput(command)
-	return s
+	return fmt.Sprintf("git diff %s%s %s %s %s", colorArg, c.diffArgs(forStaging), cachedArg, trackedArg, fileName)
 }
 
 func (c *GitCommand) ApplyPatch(patch string) (string, error) {
@@ -617,9 +1032,10 @@ func (c *GitCommand) ApplyPatch(patch string) (string, error) {
 	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git apply --cached %s", c.OSCommand.Quote(filename)))
 }
 
-func (c *GitCommand) FastForward(branchName string) error {
-	upstream := "origin" // hardcoding for now
-	return c.OSCommand.RunCommand(fmt.Sprintf("git fetch %s %s:%s", upstream, branchName, branchName))
+// FastForward updates a local branch from its remote counterpart without
+// checking it out
+func (c *GitCommand) FastForward(branchName string, remote string, remoteBranch string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git fetch %s %s:%s", remote, remoteBranch, branchName))
 }
 
 func (c *GitCommand) RunSkipEditorCommand(command string) error {
@@ -864,8 +1280,16 @@ func (c *GitCommand) GetCommitFiles(commitSha string) ([]*CommitFile, error) {
 
 // ShowCommitFile get the diff of specified commit file
 func (c *GitCommand) ShowCommitFile(commitSha, fileName string) (string, error) {
-	cmd := fmt.Sprintf("git show --color %s -- %s", commitSha, fileName)
-	return c.OSCommand.RunCommandWithOutput(cmd)
+	diff, err := c.OSCommand.RunCommandWithOutput(c.ShowCommitFileCommand(commitSha, fileName))
+	if err != nil {
+		return "", err
+	}
+	return c.page(diff), nil
+}
+
+// ShowCommitFileCommand returns the command that ShowCommitFile runs
+func (c *GitCommand) ShowCommitFileCommand(commitSha, fileName string) string {
+	return fmt.Sprintf("git show %s%s %s -- %s", c.colorArg(), c.diffArgs(false), commitSha, fileName)
 }
 
 // CheckoutFile checks out the file for the given commit
@@ -948,8 +1372,16 @@ func (c *GitCommand) ResetSoftHead() error {
 
 // DiffCommits show diff between commits
 func (c *GitCommand) DiffCommits(sha1, sha2 string) (string, error) {
-	cmd := fmt.Sprintf("git diff --color %s %s", sha1, sha2)
-	return c.OSCommand.RunCommandWithOutput(cmd)
+	diff, err := c.OSCommand.RunCommandWithOutput(c.DiffCommitsCommand(sha1, sha2))
+	if err != nil {
+		return "", err
+	}
+	return c.page(diff), nil
+}
+
+// DiffCommitsCommand returns the command that DiffCommits runs
+func (c *GitCommand) DiffCommitsCommand(sha1, sha2 string) string {
+	return fmt.Sprintf("git diff %s%s %s %s", c.colorArg(), c.diffArgs(false), sha1, sha2)
 }
 
 // CreateFixupCommit creates a commit that fixes up a previous commit
@@ -1,16 +1,20 @@
 package commands
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/go-errors/errors"
+	"github.com/jesseduffield/lazygit/pkg/config"
 	"github.com/jesseduffield/lazygit/pkg/i18n"
 	"github.com/jesseduffield/lazygit/pkg/test"
+	"github.com/jesseduffield/lazygit/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	gogit "gopkg.in/src-d/go-git.v4"
 )
@@ -265,6 +269,55 @@ func TestNewGitCommand(t *testing.T) {
 	}
 }
 
+// TestGitCommandIgnoredDirectories is a function.
+func TestGitCommandIgnoredDirectories(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"ls-files", "-z", "--others", "--ignored", "--exclude-standard", "--directory"}, args)
+		return exec.Command("printf", `build/\0debug.log\0vendor/cache/\0`)
+	}
+
+	directories, err := gitCmd.IgnoredDirectories()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"build", "vendor/cache"}, directories)
+}
+
+// TestGitCommandIgnoredPaths is a function.
+func TestGitCommandIgnoredPaths(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"None of the paths are ignored",
+			func(string, ...string) *exec.Cmd {
+				return exec.Command("false")
+			},
+			[]string{},
+		},
+		{
+			"Some of the paths are ignored",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git check-ignore -z --stdin", args[1])
+				return exec.Command("printf", `debug.log\0build/main.o\0`)
+			},
+			[]string{"debug.log", "build/main.o"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			assert.EqualValues(t, s.expected, gitCmd.IgnoredPaths([]string{"main.go", "debug.log", "build/main.o"}))
+		})
+	}
+}
+
 // TestGitCommandGetStashEntries is a function.
 func TestGitCommandGetStashEntries(t *testing.T) {
 	type scenario struct {
@@ -372,7 +425,6 @@ func TestGitCommandGetStatusFiles(t *testing.T) {
 						HasMergeConflicts:       false,
 						HasInlineMergeConflicts: false,
 						DisplayString:           "MM file1.txt",
-						Type:                    "other",
 						ShortStatus:             "MM",
 					},
 					{
@@ -384,7 +436,6 @@ func TestGitCommandGetStatusFiles(t *testing.T) {
 						HasMergeConflicts:       false,
 						HasInlineMergeConflicts: false,
 						DisplayString:           "A  file3.txt",
-						Type:                    "other",
 						ShortStatus:             "A ",
 					},
 					{
@@ -396,7 +447,6 @@ func TestGitCommandGetStatusFiles(t *testing.T) {
 						HasMergeConflicts:       false,
 						HasInlineMergeConflicts: false,
 						DisplayString:           "AM file2.txt",
-						Type:                    "other",
 						ShortStatus:             "AM",
 					},
 					{
@@ -408,7 +458,6 @@ func TestGitCommandGetStatusFiles(t *testing.T) {
 						HasMergeConflicts:       false,
 						HasInlineMergeConflicts: false,
 						DisplayString:           "?? file4.txt",
-						Type:                    "other",
 						ShortStatus:             "??",
 					},
 					{
@@ -420,7 +469,6 @@ func TestGitCommandGetStatusFiles(t *testing.T) {
 						HasMergeConflicts:       true,
 						HasInlineMergeConflicts: true,
 						DisplayString:           "UU file5.txt",
-						Type:                    "other",
 						ShortStatus:             "UU",
 					},
 				}
@@ -440,6 +488,117 @@ func TestGitCommandGetStatusFiles(t *testing.T) {
 	}
 }
 
+// TestGitCommandGetStatusFilesUntrackedFiles is a function.
+func TestGitCommandGetStatusFilesUntrackedFiles(t *testing.T) {
+	type scenario struct {
+		testName       string
+		untrackedFiles string
+		trackedOnly    bool
+		expected       []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Showing all untracked files",
+			"all",
+			false,
+			[]string{"status", "--untracked-files=all", "--porcelain"},
+		},
+		{
+			"Scanning for untracked files lazily",
+			"lazy",
+			false,
+			[]string{"status", "--untracked-files=all", "--porcelain"},
+		},
+		{
+			"Hiding untracked files",
+			"hidden",
+			false,
+			[]string{"status", "--untracked-files=no", "--porcelain"},
+		},
+		{
+			"Unknown mode",
+			"some",
+			false,
+			[]string{"status", "--untracked-files=all", "--porcelain"},
+		},
+		{
+			"Only tracked files",
+			"all",
+			true,
+			[]string{"status", "--untracked-files=no", "--porcelain"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.Config.GetUserConfig().Set("git.untrackedFiles", s.untrackedFiles)
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expected, args)
+				return exec.Command("echo")
+			}
+
+			if s.trackedOnly {
+				gitCmd.GetTrackedStatusFiles()
+			} else {
+				gitCmd.GetStatusFiles()
+			}
+		})
+	}
+}
+
+// TestGitCommandGitStatusOptionalLocks is a function.
+func TestGitCommandGitStatusOptionalLocks(t *testing.T) {
+	type scenario struct {
+		testName        string
+		gitConfig       map[string]string
+		expectNoLocking bool
+	}
+
+	scenarios := []scenario{
+		{
+			"No caches",
+			map[string]string{},
+			true,
+		},
+		{
+			"Untracked cache switched off",
+			map[string]string{"core.untrackedCache": "false"},
+			true,
+		},
+		{
+			"Untracked cache",
+			map[string]string{"core.untrackedCache": "true"},
+			false,
+		},
+		{
+			"File system monitor",
+			map[string]string{"core.fsmonitor": ".git/hooks/fsmonitor-watchman"},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getLocalGitConfig = func(key string) (string, error) {
+				return s.gitConfig[key], nil
+			}
+			var statusCmd *exec.Cmd
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				statusCmd = exec.Command("echo")
+				return statusCmd
+			}
+
+			_, err := gitCmd.GitStatus("all")
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectNoLocking, utils.IncludesString(statusCmd.Env, "GIT_OPTIONAL_LOCKS=0"))
+		})
+	}
+}
+
 // TestGitCommandStashDo is a function.
 func TestGitCommandStashDo(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
@@ -710,15 +869,100 @@ func TestGitCommandDeleteBranch(t *testing.T) {
 
 // TestGitCommandMerge is a function.
 func TestGitCommandMerge(t *testing.T) {
-	gitCmd := NewDummyGitCommand()
-	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
-		assert.EqualValues(t, "git", cmd)
-		assert.EqualValues(t, []string{"merge", "--no-edit", "test"}, args)
+	type scenario struct {
+		testName  string
+		mergeFlag string
+		message   string
+		expected  []string
+	}
 
-		return exec.Command("echo")
+	scenarios := []scenario{
+		{
+			"Regular merge",
+			"",
+			"",
+			[]string{"merge", "--no-edit", "test"},
+		},
+		{
+			"Merge without fast-forwarding",
+			"--no-ff",
+			"",
+			[]string{"merge", "--no-edit", "--no-ff", "test"},
+		},
+		{
+			"Fast-forward only merge",
+			"--ff-only",
+			"",
+			[]string{"merge", "--no-edit", "--ff-only", "test"},
+		},
+		{
+			"Merge with a custom message",
+			"--no-ff",
+			"Merge test into master",
+			[]string{"merge", "--no-edit", "--no-ff", "-m", "Merge test into master", "test"},
+		},
+		{
+			"Squash merge ignores the message",
+			"--squash",
+			"Merge test into master",
+			[]string{"merge", "--no-edit", "--squash", "test"},
+		},
 	}
 
-	assert.NoError(t, gitCmd.Merge("test"))
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expected, args)
+
+				return exec.Command("echo")
+			}
+
+			assert.NoError(t, gitCmd.Merge("test", s.mergeFlag, s.message))
+		})
+	}
+}
+
+// TestGitCommandReconcileWithUpstream is a function.
+func TestGitCommandReconcileWithUpstream(t *testing.T) {
+	type scenario struct {
+		testName string
+		mode     string
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Merge mode",
+			PullModeMerge,
+			[]string{"merge", "--no-edit", "@{u}"},
+		},
+		{
+			"Fast-forward only mode",
+			PullModeFastForwardOnly,
+			[]string{"merge", "--no-edit", "--ff-only", "@{u}"},
+		},
+		{
+			"Rebase mode",
+			PullModeRebase,
+			[]string{"rebase", "--interactive", "--autostash", "@{u}"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expected, args)
+
+				return exec.Command("echo")
+			}
+
+			assert.NoError(t, gitCmd.ReconcileWithUpstream(s.mode))
+		})
+	}
 }
 
 // TestGitCommandUsingGpg is a function.
@@ -901,7 +1145,169 @@ func TestGitCommandCommit(t *testing.T) {
 			gitCmd := NewDummyGitCommand()
 			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
 			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.Commit("test", s.flags))
+			s.test(gitCmd.Commit("test", s.flags, nil))
+		})
+	}
+}
+
+// TestGitCommandCommitWithStream is a function.
+func TestGitCommandCommitWithStream(t *testing.T) {
+	type scenario struct {
+		testName       string
+		command        func(string, ...string) *exec.Cmd
+		expectedStream string
+		test           func(*exec.Cmd, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Hook output is streamed",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"commit", "-m", "test"}, args)
+
+				return exec.Command("sh", "-c", "echo linting >&2; echo committed")
+			},
+			"linting\n",
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Hook fails",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("sh", "-c", "echo lint failed >&2; exit 1")
+			},
+			"lint failed\n",
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.EqualError(t, err, "lint failed\n")
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getGlobalGitConfig = func(string) (string, error) {
+				return "false", nil
+			}
+			gitCmd.OSCommand.command = s.command
+			stream := &bytes.Buffer{}
+			s.test(gitCmd.Commit("test", "", stream))
+			assert.EqualValues(t, s.expectedStream, stream.String())
+		})
+	}
+}
+
+// TestGitCommandHasHook is a function.
+func TestGitCommandHasHook(t *testing.T) {
+	dotGitDir, err := ioutil.TempDir("", "lazygit-hooks")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dotGitDir)
+
+	hooksDir := filepath.Join(dotGitDir, "hooks")
+	assert.NoError(t, os.Mkdir(hooksDir, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(hooksDir, "pre-commit"), []byte("#!/bin/sh\n"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(hooksDir, "pre-push.sample"), []byte("#!/bin/sh\n"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(hooksDir, "commit-msg"), []byte("#!/bin/sh\n"), 0644))
+
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.Platform.os = "linux"
+	gitCmd.DotGitDir = dotGitDir
+	gitCmd.getLocalGitConfig = func(string) (string, error) {
+		return "", nil
+	}
+	gitCmd.getGlobalGitConfig = func(string) (string, error) {
+		return "", nil
+	}
+
+	assert.True(t, gitCmd.HasHook("pre-commit"))
+	assert.False(t, gitCmd.HasHook("pre-push"))
+	// git skips hooks that aren't executable
+	assert.False(t, gitCmd.HasHook("commit-msg"))
+
+	gitCmd.getLocalGitConfig = func(key string) (string, error) {
+		assert.EqualValues(t, "core.hooksPath", key)
+		return filepath.Join(dotGitDir, "elsewhere"), nil
+	}
+	assert.False(t, gitCmd.HasHook("pre-commit"))
+}
+
+// TestGitCommandGetCommitMessage is a function.
+func TestGitCommandGetCommitMessage(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"log", "-1", "--format=%B", "HEAD"}, args)
+
+		return exec.Command("echo", "subject\n\nbody\n")
+	}
+
+	message, err := gitCmd.GetCommitMessage("HEAD")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "subject\n\nbody", message)
+}
+
+// TestGitCommandGetMergeMessage is a function.
+func TestGitCommandGetMergeMessage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazygit-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gitCmd := NewDummyGitCommand()
+	gitCmd.DotGitDir = dir
+	assert.EqualValues(t, "", gitCmd.GetMergeMessage())
+
+	content := "Merge branch 'test'\n\n# Conflicts:\n#\tfile.txt\n"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "MERGE_MSG"), []byte(content), 0644))
+	assert.EqualValues(t, "Merge branch 'test'", gitCmd.GetMergeMessage())
+}
+
+// TestGitCommandGetCommitTemplate is a function.
+func TestGitCommandGetCommitTemplate(t *testing.T) {
+	file, err := ioutil.TempFile("", "lazygit-template")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("# describe the change\nticket: \n\nwhy:\n")
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	type scenario struct {
+		testName           string
+		getLocalGitConfig  func(string) (string, error)
+		getGlobalGitConfig func(string) (string, error)
+		expected           string
+	}
+
+	scenarios := []scenario{
+		{
+			"No template configured",
+			func(string) (string, error) { return "", nil },
+			func(string) (string, error) { return "", nil },
+			"",
+		},
+		{
+			"Template configured globally",
+			func(string) (string, error) { return "", nil },
+			func(string) (string, error) { return file.Name(), nil },
+			"ticket: \n\nwhy:",
+		},
+		{
+			"Configured template does not exist",
+			func(string) (string, error) { return "/does/not/exist", nil },
+			func(string) (string, error) { return file.Name(), nil },
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getLocalGitConfig = s.getLocalGitConfig
+			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
+			assert.EqualValues(t, s.expected, gitCmd.GetCommitTemplate())
 		})
 	}
 }
@@ -979,22 +1385,22 @@ func TestGitCommandAmendHead(t *testing.T) {
 // TestGitCommandPush is a function.
 func TestGitCommandPush(t *testing.T) {
 	type scenario struct {
-		testName  string
-		command   func(string, ...string) *exec.Cmd
-		forcePush bool
-		test      func(error)
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		opts     PushOpts
+		test     func(error)
 	}
 
 	scenarios := []scenario{
 		{
-			"Push with force disabled",
+			"Push to the configured upstream",
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"push", "-u", "origin", "test"}, args)
+				assert.EqualValues(t, []string{"push"}, args)
 
 				return exec.Command("echo")
 			},
-			false,
+			PushOpts{},
 			func(err error) {
 				assert.NoError(t, err)
 			},
@@ -1003,11 +1409,37 @@ func TestGitCommandPush(t *testing.T) {
 			"Push with force enabled",
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"push", "--force-with-lease", "-u", "origin", "test"}, args)
+				assert.EqualValues(t, []string{"push", "--force-with-lease", "fork", "test"}, args)
 
 				return exec.Command("echo")
 			},
-			true,
+			PushOpts{Force: true, Remote: "fork", RemoteBranch: "test"},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Push setting the upstream",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"push", "-u", "fork", "test"}, args)
+
+				return exec.Command("echo")
+			},
+			PushOpts{SetUpstream: true, Remote: "fork"},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Push to a differently named branch without hooks, including tags",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"push", "--no-verify", "--follow-tags", "fork", "test:other"}, args)
+
+				return exec.Command("echo")
+			},
+			PushOpts{NoVerify: true, Tags: true, Remote: "fork", RemoteBranch: "other"},
 			func(err error) {
 				assert.NoError(t, err)
 			},
@@ -1019,7 +1451,7 @@ func TestGitCommandPush(t *testing.T) {
 				assert.EqualValues(t, []string{"push", "-u", "origin", "test"}, args)
 				return exec.Command("test")
 			},
-			false,
+			PushOpts{SetUpstream: true, Remote: "origin"},
 			func(err error) {
 				assert.Error(t, err)
 			},
@@ -1030,14 +1462,88 @@ func TestGitCommandPush(t *testing.T) {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd := NewDummyGitCommand()
 			gitCmd.OSCommand.command = s.command
-			err := gitCmd.Push("test", s.forcePush, func(passOrUname string) string {
+			err := gitCmd.Push("test", s.opts, func(passOrUname string) string {
 				return "\n"
-			})
+			}, nil)
 			s.test(err)
 		})
 	}
 }
 
+// TestGitCommandGetUpstream is a function.
+func TestGitCommandGetUpstream(t *testing.T) {
+	type scenario struct {
+		testName          string
+		getLocalGitConfig func(string) (string, error)
+		test              func(string, string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Branch tracks a branch on another remote",
+			func(key string) (string, error) {
+				switch key {
+				case "branch.test.remote":
+					return "fork", nil
+				case "branch.test.merge":
+					return "refs/heads/feature/test", nil
+				}
+				return "", errors.New("unexpected key " + key)
+			},
+			func(remote string, remoteBranch string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "fork", remote)
+				assert.EqualValues(t, "feature/test", remoteBranch)
+			},
+		},
+		{
+			"Branch has no upstream",
+			func(key string) (string, error) {
+				return "", errors.New("exit status 1")
+			},
+			func(remote string, remoteBranch string, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getLocalGitConfig = s.getLocalGitConfig
+			s.test(gitCmd.GetUpstream("test"))
+		})
+	}
+}
+
+// TestGitCommandGetRemotes is a function.
+func TestGitCommandGetRemotes(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"remote"}, args)
+
+		return exec.Command("echo", "origin\nupstream")
+	}
+
+	remotes, err := gitCmd.GetRemotes()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"origin", "upstream"}, remotes)
+}
+
+// TestGitCommandFastForward is a function.
+func TestGitCommandFastForward(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"fetch", "fork", "feature:test"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.FastForward("test", "fork", "feature"))
+}
+
 // TestGitCommandCatFile is a function.
 func TestGitCommandCatFile(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
@@ -1116,17 +1622,19 @@ func TestGitCommandUnstageFile(t *testing.T) {
 // TestGitCommandIsInMergeState is a function.
 func TestGitCommandIsInMergeState(t *testing.T) {
 	type scenario struct {
-		testName string
-		command  func(string, ...string) *exec.Cmd
-		test     func(bool, error)
+		testName  string
+		mergeHead bool
+		command   func(string, ...string) *exec.Cmd
+		test      func(bool, error)
 	}
 
 	scenarios := []scenario{
 		{
-			"An error occurred when running status command",
+			"An error occurred when listing unmerged files",
+			false,
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"status", "--untracked-files=all"}, args)
+				assert.EqualValues(t, []string{"ls-files", "--unmerged"}, args)
 
 				return exec.Command("test")
 			},
@@ -1137,9 +1645,10 @@ func TestGitCommandIsInMergeState(t *testing.T) {
 		},
 		{
 			"Is not in merge state",
+			false,
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"status", "--untracked-files=all"}, args)
+				assert.EqualValues(t, []string{"ls-files", "--unmerged"}, args)
 				return exec.Command("echo")
 			},
 			func(isInMergeState bool, err error) {
@@ -1148,11 +1657,11 @@ func TestGitCommandIsInMergeState(t *testing.T) {
 			},
 		},
 		{
-			"Command output contains conclude merge",
+			"Merge to conclude",
+			true,
 			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"status", "--untracked-files=all"}, args)
-				return exec.Command("echo", "'conclude merge'")
+				assert.Fail(t, "should not list unmerged files when there's a merge head")
+				return exec.Command("echo")
 			},
 			func(isInMergeState bool, err error) {
 				assert.True(t, isInMergeState)
@@ -1160,11 +1669,12 @@ func TestGitCommandIsInMergeState(t *testing.T) {
 			},
 		},
 		{
-			"Command output contains unmerged paths",
+			"Unmerged paths",
+			false,
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"status", "--untracked-files=all"}, args)
-				return exec.Command("echo", "'unmerged paths'")
+				assert.EqualValues(t, []string{"ls-files", "--unmerged"}, args)
+				return exec.Command("echo", "100644 f2e4113f0e8a3a1c3b1e0c9f4bd5a4b1c6c6e5e1 1\tfile.txt")
 			},
 			func(isInMergeState bool, err error) {
 				assert.True(t, isInMergeState)
@@ -1175,7 +1685,15 @@ func TestGitCommandIsInMergeState(t *testing.T) {
 
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
+			dotGitDir, err := ioutil.TempDir("", "lazygit-test")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dotGitDir)
+			if s.mergeHead {
+				assert.NoError(t, ioutil.WriteFile(filepath.Join(dotGitDir, "MERGE_HEAD"), []byte("abc\n"), 0644))
+			}
+
 			gitCmd := NewDummyGitCommand()
+			gitCmd.DotGitDir = dotGitDir
 			gitCmd.OSCommand.command = s.command
 			s.test(gitCmd.IsInMergeState())
 		})
@@ -1471,6 +1989,69 @@ func TestGitCommandShow(t *testing.T) {
 	}
 }
 
+// TestGitCommandShowCommands is a function.
+func TestGitCommandShowCommands(t *testing.T) {
+	type scenario struct {
+		testName string
+		arg      string
+		command  func(string, ...string) *exec.Cmd
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"regular commit",
+			"456abcde",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-list --parents -n 1 456abcde",
+					Replace: "echo 456abcde 1a6a69a",
+				},
+			}),
+			[]string{"git show --color 456abcde"},
+		},
+		{
+			"first commit",
+			"456abcde",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-list --parents -n 1 456abcde",
+					Replace: "echo 456abcde",
+				},
+			}),
+			[]string{"git show --color 456abcde"},
+		},
+		{
+			"merge commit",
+			"456abcde",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-list --parents -n 1 456abcde",
+					Replace: "echo 456abcde 1a6a69a 3b51d7c",
+				},
+			}),
+			[]string{"git show --color 456abcde", "git diff --color 1a6a69a...3b51d7c"},
+		},
+		{
+			"unknown commit",
+			"456abcde",
+			func(string, ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			[]string{"git show --color 456abcde"},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			assert.EqualValues(t, s.expected, gitCmd.ShowCommands(s.arg))
+		})
+	}
+}
+
 // TestGitCommandCheckout is a function.
 func TestGitCommandCheckout(t *testing.T) {
 	type scenario struct {
@@ -1609,7 +2190,149 @@ func TestGitCommandDiff(t *testing.T) {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd := NewDummyGitCommand()
 			gitCmd.OSCommand.command = s.command
-			gitCmd.Diff(s.file, s.plain)
+			gitCmd.Diff(s.file, s.plain, false)
+		})
+	}
+}
+
+// TestGitCommandDiffWithPager is a function.
+func TestGitCommandDiffWithPager(t *testing.T) {
+	type scenario struct {
+		testName   string
+		pager      string
+		command    func(string, ...string) *exec.Cmd
+		plain      bool
+		forStaging bool
+		expected   string
+	}
+
+	gitDiff := func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		return exec.Command("echo", "diff")
+	}
+
+	scenarios := []scenario{
+		{
+			"No pager configured",
+			"",
+			gitDiff,
+			false,
+			false,
+			"diff\n",
+		},
+		{
+			"Pager given the width",
+			"delta --width={{columnWidth}}",
+			func(cmd string, args ...string) *exec.Cmd {
+				if cmd == "git" {
+					return gitDiff(cmd, args...)
+				}
+				assert.EqualValues(t, "bash", cmd)
+				assert.EqualValues(t, []string{"-c", "delta --width=80"}, args)
+
+				return exec.Command("sh", "-c", "tr a-z A-Z && echo $COLUMNS")
+			},
+			false,
+			false,
+			"DIFF\n80\n",
+		},
+		{
+			"Pager skipped for the staging panel",
+			"delta",
+			gitDiff,
+			false,
+			true,
+			"diff\n",
+		},
+		{
+			"Pager skipped for plain diffs",
+			"delta",
+			gitDiff,
+			true,
+			false,
+			"diff\n",
+		},
+		{
+			"Pager fails to run",
+			"delta",
+			func(cmd string, args ...string) *exec.Cmd {
+				if cmd == "git" {
+					return gitDiff(cmd, args...)
+				}
+				return exec.Command("false")
+			},
+			false,
+			false,
+			"diff\n",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.Platform = &Platform{shell: "bash", shellArg: "-c"}
+			gitCmd.OSCommand.command = s.command
+			gitCmd.Config.GetUserConfig().Set("git.paging.pager", s.pager)
+			gitCmd.SetPagerWidth(80)
+			file := &File{Name: "test.txt", Tracked: true}
+			assert.EqualValues(t, s.expected, gitCmd.Diff(file, s.plain, s.forStaging))
+		})
+	}
+}
+
+// TestGitCommandDiffOptions is a function.
+func TestGitCommandDiffOptions(t *testing.T) {
+	type scenario struct {
+		testName     string
+		options      *config.DiffOptions
+		forStaging   bool
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
+		{
+			"No options set",
+			nil,
+			false,
+			[]string{"diff", "--color", "--", "test.txt"},
+		},
+		{
+			"Defaults",
+			&config.DiffOptions{ContextLines: 3},
+			false,
+			[]string{"diff", "--color", "--", "test.txt"},
+		},
+		{
+			"Every option",
+			&config.DiffOptions{ContextLines: 0, IgnoreWhitespace: true, WordDiff: true, RenameThreshold: 40, CopyThreshold: 75},
+			false,
+			[]string{"diff", "--color", "-U0", "-w", "--word-diff=color", "-M40%", "-C75%", "--", "test.txt"},
+		},
+		{
+			"Staging only takes the context size",
+			&config.DiffOptions{ContextLines: 5, IgnoreWhitespace: true, WordDiff: true, RenameThreshold: 40},
+			true,
+			[]string{"diff", "--color", "-U5", "--", "test.txt"},
+		},
+		{
+			"Staging needs at least one line of context",
+			&config.DiffOptions{ContextLines: 0},
+			true,
+			[]string{"diff", "--color", "-U1", "--", "test.txt"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.DiffOptions = s.options
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			}
+			gitCmd.Diff(&File{Name: "test.txt", Tracked: true}, false, s.forStaging)
 		})
 	}
 }
@@ -1931,6 +2654,7 @@ func TestGitCommandShowCommitFile(t *testing.T) {
 		testName  string
 		commitSha string
 		fileName  string
+		pager     string
 		command   func(string, ...string) *exec.Cmd
 		test      func(string, error)
 	}
@@ -1940,6 +2664,7 @@ func TestGitCommandShowCommitFile(t *testing.T) {
 			"valid case",
 			"123456",
 			"hello.txt",
+			"",
 			test.CreateMockCommand(t, []*test.CommandSwapper{
 				{
 					Expect:  "git show --color 123456 -- hello.txt",
@@ -1951,6 +2676,24 @@ func TestGitCommandShowCommitFile(t *testing.T) {
 				assert.Equal(t, "hello", str)
 			},
 		},
+		{
+			"With a pager",
+			"123456",
+			"hello.txt",
+			"delta",
+			func(cmd string, args ...string) *exec.Cmd {
+				if cmd == "git" {
+					assert.EqualValues(t, []string{"show", "--color", "123456", "--", "hello.txt"}, args)
+					return exec.Command("echo", "-n", "hello")
+				}
+				assert.EqualValues(t, []string{"-c", "delta"}, args)
+				return exec.Command("tr", "a-z", "A-Z")
+			},
+			func(str string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "HELLO", str)
+			},
+		},
 	}
 
 	gitCmd := NewDummyGitCommand()
@@ -1958,6 +2701,7 @@ func TestGitCommandShowCommitFile(t *testing.T) {
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd.OSCommand.command = s.command
+			gitCmd.Config.GetUserConfig().Set("git.paging.pager", s.pager)
 			s.test(gitCmd.ShowCommitFile(s.commitSha, s.fileName))
 		})
 	}
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"bytes"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -8,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-errors/errors"
 
@@ -37,6 +40,10 @@ type OSCommand struct {
 	command            func(string, ...string) *exec.Cmd
 	getGlobalGitConfig func(string) (string, error)
 	getenv             func(string) string
+	commandLogger      func(CmdLogEntry)
+	// task is what our commands are run for when we're a copy made by
+	// WithTask, in which case cancelling the task kills them
+	task *Task
 }
 
 // NewOSCommand os command runner
@@ -61,12 +68,18 @@ func (c *OSCommand) SetCommand(cmd func(string, ...string) *exec.Cmd) {
 func (c *OSCommand) RunCommandWithOutput(command string) (string, error) {
 	c.Log.WithField("command", command).Info("RunCommand")
 	cmd := c.ExecutableFromString(command)
-	return sanitisedCommandOutput(cmd.CombinedOutput())
+	start := time.Now()
+	output, err := c.combinedOutput(cmd)
+	c.logCommand(command, start, string(output), err)
+	return sanitisedCommandOutput(output, err)
 }
 
 // RunExecutableWithOutput runs an executable file and returns its output
 func (c *OSCommand) RunExecutableWithOutput(cmd *exec.Cmd) (string, error) {
-	return sanitisedCommandOutput(cmd.CombinedOutput())
+	start := time.Now()
+	output, err := c.combinedOutput(cmd)
+	c.logCommand(strings.Join(cmd.Args, " "), start, string(output), err)
+	return sanitisedCommandOutput(output, err)
 }
 
 // RunExecutable runs an executable file and returns an error if there was one
@@ -85,31 +98,106 @@ func (c *OSCommand) ExecutableFromString(commandStr string) *exec.Cmd {
 
 // RunCommandWithOutputLive runs RunCommandWithOutputLiveWrapper
 func (c *OSCommand) RunCommandWithOutputLive(command string, output func(string) string) error {
-	return RunCommandWithOutputLiveWrapper(c, command, output)
+	return RunCommandWithOutputLiveWrapper(c, command, output, nil)
+}
+
+// RunCommandWithStream runs a command, copying what it writes to stderr to the
+// given writer as it comes in. This is where git sends the output of hooks, so
+// it lets us show the progress of e.g. a slow pre-commit hook while it runs.
+// stdout is left out because for most commands it only holds the result
+func (c *OSCommand) RunCommandWithStream(command string, stream io.Writer) error {
+	c.Log.WithField("command", command).Info("RunCommand")
+	cmd := c.ExecutableFromString(command)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = io.MultiWriter(&stderr, stream)
+	start := time.Now()
+	err := c.runCommand(cmd)
+	output := append(stdout.Bytes(), stderr.Bytes()...)
+	c.logCommand(command, start, string(output), err)
+	_, err = sanitisedCommandOutput(output, err)
+	return err
+}
+
+// the kinds of credentials that DetectUnamePass can ask the user for
+const (
+	CredentialUsername   = "username"
+	CredentialPassword   = "password"
+	CredentialPassphrase = "passphrase"
+	CredentialHostKey    = "hostKey"
+	CredentialOTP        = "otp"
+)
+
+// credentialPrompt pairs a pattern matching a prompt written to the tty by git
+// (or by ssh on git's behalf) with the kind of credential it asks for
+type credentialPrompt struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+var defaultCredentialPrompts = []credentialPrompt{
+	{CredentialUsername, regexp.MustCompile(`Username\s*for\s*'.+':`)},
+	{CredentialPassword, regexp.MustCompile(`Password\s*for\s*'.+':`)},
+	{CredentialPassword, regexp.MustCompile(`'s\s*password:\s*$`)},
+	{CredentialPassphrase, regexp.MustCompile(`Enter\s*passphrase\s*for\s*(key\s*)?'.+':`)},
+	{CredentialHostKey, regexp.MustCompile(`Are\s*you\s*sure\s*you\s*want\s*to\s*continue\s*connecting\s*\(yes/no(/\[fingerprint\])?\)\?`)},
+	{CredentialOTP, regexp.MustCompile(`(?i)(one-time\s*password|verification\s*code|two-factor\s*(authentication\s*)?code|\b2fa\s*code|\botp\b)[^:]*:\s*$`)},
+}
+
+// credentialPrompts returns the prompts configured by the user under
+// git.credentialPrompts, followed by the default ones, so that user patterns
+// take precedence. Patterns that don't compile are logged and left out
+func (c *OSCommand) credentialPrompts() []credentialPrompt {
+	prompts := []credentialPrompt{}
+	userPrompts := c.Config.GetUserConfig().GetStringMapStringSlice("git.credentialPrompts")
+	// viper lowercases keys, so we match the kinds case-insensitively
+	for _, kind := range []string{CredentialUsername, CredentialPassword, CredentialPassphrase, CredentialHostKey, CredentialOTP} {
+		for _, pattern := range userPrompts[strings.ToLower(kind)] {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				c.Log.Errorf("invalid credential prompt pattern %s: %v", pattern, err)
+				continue
+			}
+			prompts = append(prompts, credentialPrompt{kind, re})
+		}
+	}
+	return append(prompts, defaultCredentialPrompts...)
+}
+
+// detectCredentialPrompt returns the kind of credential the given tty text asks
+// for, or an empty string if it doesn't end in one of the given prompts
+func detectCredentialPrompt(prompts []credentialPrompt, ttyText string) string {
+	for _, prompt := range prompts {
+		if prompt.pattern.MatchString(ttyText) {
+			return prompt.kind
+		}
+	}
+	return ""
 }
 
 // DetectUnamePass detect a username / password question in a command
 // ask is a function that gets executen when this function detect you need to fillin a password
-// The ask argument will be "username" or "password" and expects the user's password or username back
+// The ask argument will be one of the Credential* kinds (e.g. "username" or
+// "password") and expects the user's answer back
 func (c *OSCommand) DetectUnamePass(command string, ask func(string) string) error {
+	return c.DetectUnamePassWithStream(command, ask, nil)
+}
+
+// DetectUnamePassWithStream is DetectUnamePass, but also copies the command's
+// output to stream as it comes in, if stream isn't nil
+func (c *OSCommand) DetectUnamePassWithStream(command string, ask func(string) string, stream io.Writer) error {
+	prompts := c.credentialPrompts()
 	ttyText := ""
-	errMessage := c.RunCommandWithOutputLive(command, func(word string) string {
+	errMessage := RunCommandWithOutputLiveWrapper(c, command, func(word string) string {
 		ttyText = ttyText + " " + word
 
-		prompts := map[string]string{
-			"password": `Password\s*for\s*'.+':`,
-			"username": `Username\s*for\s*'.+':`,
-		}
-
-		for askFor, pattern := range prompts {
-			if match, _ := regexp.MatchString(pattern, ttyText); match {
-				ttyText = ""
-				return ask(askFor)
-			}
+		if askFor := detectCredentialPrompt(prompts, ttyText); askFor != "" {
+			ttyText = ""
+			return ask(askFor)
 		}
 
 		return ""
-	})
+	}, stream)
 	return errMessage
 }
 
@@ -135,10 +223,25 @@ func (c *OSCommand) FileType(path string) string {
 func (c *OSCommand) RunDirectCommand(command string) (string, error) {
 	c.Log.WithField("command", command).Info("RunDirectCommand")
 
-	return sanitisedCommandOutput(
-		c.command(c.Platform.shell, c.Platform.shellArg, command).
-			CombinedOutput(),
-	)
+	start := time.Now()
+	output, err := c.combinedOutput(c.command(c.Platform.shell, c.Platform.shellArg, command))
+	c.logCommand(command, start, string(output), err)
+	return sanitisedCommandOutput(output, err)
+}
+
+// RunDirectCommandWithInput runs a command through the shell with the given
+// input on its stdin, returning what it writes to stdout. Any extra env
+// entries are added to the command's environment
+func (c *OSCommand) RunDirectCommandWithInput(command string, input string, env ...string) (string, error) {
+	c.Log.WithField("command", command).Info("RunDirectCommandWithInput")
+
+	cmd := c.command(c.Platform.shell, c.Platform.shellArg, command)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = strings.NewReader(input)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	err := c.runCommand(cmd)
+	return sanitisedCommandOutput(stdout.Bytes(), err)
 }
 
 func sanitisedCommandOutput(output []byte, err error) (string, error) {
@@ -221,6 +324,16 @@ func (c *OSCommand) Quote(message string) string {
 	return escapedQuote + message + escapedQuote
 }
 
+// ShellQuote quotes a string for the shell that runs e.g. custom commands, so
+// that the shell takes it as one argument, exactly as it is. Unlike Quote, it
+// leaves nothing in the string for the shell to expand, like $(...)
+func (c *OSCommand) ShellQuote(message string) string {
+	if c.Platform.os == "windows" {
+		return `"` + strings.Replace(message, `"`, `""`, -1) + `"`
+	}
+	return "'" + strings.Replace(message, "'", `'\''`, -1) + "'"
+}
+
 // Unquote removes wrapping quotations marks if they are present
 // this is needed for removing quotes from staged filenames with spaces
 func (c *OSCommand) Unquote(message string) string {
@@ -283,8 +396,10 @@ func (c *OSCommand) FileExists(path string) (bool, error) {
 // this is useful if you need to give your command some environment variables
 // before running it
 func (c *OSCommand) RunPreparedCommand(cmd *exec.Cmd) error {
-	out, err := cmd.CombinedOutput()
+	start := time.Now()
+	out, err := c.combinedOutput(cmd)
 	outString := string(out)
+	c.logCommand(strings.Join(cmd.Args, " "), start, outString, err)
 	c.Log.Info(outString)
 	if err != nil {
 		if len(outString) == 0 {
@@ -3,7 +3,9 @@
 package commands
 
 import (
+	"os/exec"
 	"runtime"
+	"syscall"
 )
 
 func getPlatform() *Platform {
@@ -17,3 +19,13 @@ func getPlatform() *Platform {
 		fallbackEscapedQuote: "\"",
 	}
 }
+
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills a command along with everything in its process group,
+// whose id is the same as the command's process id
+func killProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
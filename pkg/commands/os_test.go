@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -37,6 +38,83 @@ func TestOSCommandRunCommandWithOutput(t *testing.T) {
 	}
 }
 
+// TestOSCommandDetectCredentialPrompt is a function.
+func TestOSCommandDetectCredentialPrompt(t *testing.T) {
+	type scenario struct {
+		testName    string
+		ttyText     string
+		userPrompts map[string][]string
+		expected    string
+	}
+
+	scenarios := []scenario{
+		{
+			"Username prompt",
+			" Username for 'https://github.com':",
+			nil,
+			CredentialUsername,
+		},
+		{
+			"Password prompt",
+			" Password for 'https://user@github.com':",
+			nil,
+			CredentialPassword,
+		},
+		{
+			"SSH password prompt",
+			" git@example.com's password:",
+			nil,
+			CredentialPassword,
+		},
+		{
+			"SSH key passphrase prompt",
+			" Enter passphrase for key '/home/user/.ssh/id_rsa':",
+			nil,
+			CredentialPassphrase,
+		},
+		{
+			"SSH host key confirmation",
+			" The authenticity of host 'github.com' can't be established. Are you sure you want to continue connecting (yes/no/[fingerprint])?",
+			nil,
+			CredentialHostKey,
+		},
+		{
+			"One-time password prompt",
+			" One-time password (OATH) for 'user':",
+			nil,
+			CredentialOTP,
+		},
+		{
+			"Regular output",
+			" Everything up-to-date",
+			nil,
+			"",
+		},
+		{
+			"User configured prompt",
+			" Token for example.com:",
+			map[string][]string{"password": {`Token\s*for\s*.+:`}},
+			CredentialPassword,
+		},
+		{
+			"Invalid user configured prompt is skipped",
+			" Username for 'https://github.com':",
+			map[string][]string{"otp": {`(`}},
+			CredentialUsername,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			osCommand := NewDummyOSCommand()
+			if s.userPrompts != nil {
+				osCommand.Config.GetUserConfig().Set("git.credentialPrompts", s.userPrompts)
+			}
+			assert.EqualValues(t, s.expected, detectCredentialPrompt(osCommand.credentialPrompts(), s.ttyText))
+		})
+	}
+}
+
 // TestOSCommandRunCommand is a function.
 func TestOSCommandRunCommand(t *testing.T) {
 	type scenario struct {
@@ -276,6 +354,52 @@ func TestOSCommandQuoteDoubleQuote(t *testing.T) {
 	assert.EqualValues(t, expected, actual)
 }
 
+// TestOSCommandShellQuote is a function.
+func TestOSCommandShellQuote(t *testing.T) {
+	type scenario struct {
+		testName string
+		message  string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			"A plain string",
+			"file.txt",
+			"'file.txt'",
+		},
+		{
+			"Spaces",
+			"a b.txt",
+			"'a b.txt'",
+		},
+		{
+			"Something for the shell to expand",
+			"$(echo hi) `echo hi` $HOME",
+			"'$(echo hi) `echo hi` $HOME'",
+		},
+		{
+			"Single quotes",
+			"it's '$(echo hi)'",
+			`'it'\''s '\''$(echo hi)'\'''`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			osCommand := NewDummyOSCommand()
+			osCommand.Platform.os = "linux"
+			quoted := osCommand.ShellQuote(s.message)
+			assert.EqualValues(t, s.expected, quoted)
+
+			// the shell should hand it to the command as it is
+			output, err := osCommand.RunDirectCommand("printf %s " + quoted)
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.message, output)
+		})
+	}
+}
+
 // TestOSCommandUnquote is a function.
 func TestOSCommandUnquote(t *testing.T) {
 	osCommand := NewDummyOSCommand()
@@ -375,3 +499,96 @@ func TestOSCommandCreateTempFile(t *testing.T) {
 		})
 	}
 }
+
+// TestTaskKill is a function.
+func TestTaskKill(t *testing.T) {
+	task := NewTask()
+	osCommand := NewDummyOSCommand()
+	taskCommand := osCommand.WithTask(task)
+	assert.EqualValues(t, 0, task.Kill())
+
+	done := make(chan error)
+	otherDone := make(chan error)
+	start := time.Now()
+	go func() {
+		// the sleep is run by the shell, so it's only killed along with the
+		// shell if the whole process group is
+		_, err := taskCommand.RunDirectCommand("sleep 10; echo done")
+		done <- err
+	}()
+	go func() {
+		// commands that aren't run for the task are left alone
+		_, err := osCommand.RunDirectCommand("sleep 1; echo done")
+		otherDone <- err
+	}()
+	for task.count() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.EqualValues(t, 1, task.Kill())
+	assert.Error(t, <-done)
+	assert.True(t, time.Since(start) < 5*time.Second)
+	assert.EqualValues(t, 0, task.count())
+	assert.NoError(t, <-otherDone)
+}
+
+// TestOSCommandStreamCommandOutput is a function.
+func TestOSCommandStreamCommandOutput(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  string
+		// how much output we want before we stop, or -1 for all of it
+		wanted   int
+		expected string
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"All of the output",
+			`sh -c "echo one; echo two"`,
+			-1,
+			"one\ntwo\n",
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			// the sleep is only killed along with the shell if the whole
+			// process group is
+			"Stopping early",
+			`sh -c "echo one; sleep 10; echo two"`,
+			1,
+			"one\n",
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"A failing command",
+			`sh -c "echo one; echo 'oh no' >&2; exit 1"`,
+			-1,
+			"one\n",
+			func(err error) {
+				assert.EqualError(t, err, "oh no\n")
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			task := NewTask()
+			osCommand := NewDummyOSCommand().WithTask(task)
+			output := ""
+			start := time.Now()
+			err := osCommand.StreamCommandOutput(s.command, func(chunk string) bool {
+				output += chunk
+				return s.wanted == -1 || len(output) < s.wanted
+			})
+			s.test(err)
+			assert.EqualValues(t, s.expected, output)
+			assert.True(t, time.Since(start) < 5*time.Second)
+			assert.EqualValues(t, 0, task.count())
+		})
+	}
+}
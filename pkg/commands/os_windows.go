@@ -1,5 +1,7 @@
 package commands
 
+import "os/exec"
+
 func getPlatform() *Platform {
 	return &Platform{
 		os:                   "windows",
@@ -9,3 +11,11 @@ func getPlatform() *Platform {
 		fallbackEscapedQuote: "\\'",
 	}
 }
+
+// there are no process groups to speak of on windows, so a command is started
+// as it is and only the command itself is killed
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
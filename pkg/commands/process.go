@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"bytes"
+	"os/exec"
+	"sync"
+)
+
+// Task keeps track of the commands run for something the user can cancel, e.g.
+// a push, so that cancelling it kills them. Commands are only tracked when
+// they're run through an OSCommand or GitCommand made for the task with
+// WithTask, which keeps e.g. the loads refreshing the panels out of it
+type Task struct {
+	mutex sync.Mutex
+	cmds  map[*exec.Cmd]bool
+}
+
+// NewTask returns a task with no commands running
+func NewTask() *Task {
+	return &Task{cmds: map[*exec.Cmd]bool{}}
+}
+
+// start starts a command, keeping track of it until it's removed
+func (t *Task) start(cmd *exec.Cmd) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if err := startInProcessGroup(cmd); err != nil {
+		return err
+	}
+	t.cmds[cmd] = true
+	return nil
+}
+
+// add keeps track of a command that's been started elsewhere
+func (t *Task) add(cmd *exec.Cmd) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.cmds[cmd] = true
+}
+
+func (t *Task) remove(cmd *exec.Cmd) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.cmds, cmd)
+}
+
+func (t *Task) count() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return len(t.cmds)
+}
+
+// Kill kills the task's commands that are still running, along with anything
+// they've started, returning how many there were
+func (t *Task) Kill() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	killed := 0
+	for cmd := range t.cmds {
+		if err := killProcessGroup(cmd); err == nil {
+			killed++
+		}
+	}
+	return killed
+}
+
+// startInProcessGroup starts a command in a process group of its own, so that
+// killing it also kills anything it has started, like the hooks run by git or
+// the commands run by a shell
+func startInProcessGroup(cmd *exec.Cmd) error {
+	// a command started on a pty is already given a session, and with it a
+	// process group, of its own
+	if cmd.SysProcAttr == nil {
+		setProcessGroup(cmd)
+	}
+	return cmd.Start()
+}
+
+// WithTask returns a copy of the OSCommand whose commands are killed when the
+// task is
+func (c *OSCommand) WithTask(task *Task) *OSCommand {
+	clone := *c
+	clone.task = task
+	return &clone
+}
+
+// startCommand starts a command, which the task we're running for, if any,
+// keeps track of until doneWithCommand is called
+func (c *OSCommand) startCommand(cmd *exec.Cmd) error {
+	if c.task == nil {
+		return startInProcessGroup(cmd)
+	}
+	return c.task.start(cmd)
+}
+
+// trackCommand hands a command that's been started elsewhere to the task we're
+// running for, if any
+func (c *OSCommand) trackCommand(cmd *exec.Cmd) {
+	if c.task != nil {
+		c.task.add(cmd)
+	}
+}
+
+func (c *OSCommand) doneWithCommand(cmd *exec.Cmd) {
+	if c.task != nil {
+		c.task.remove(cmd)
+	}
+}
+
+// runCommand runs a command, which can be cancelled along with the task we're
+// running it for
+func (c *OSCommand) runCommand(cmd *exec.Cmd) error {
+	if err := c.startCommand(cmd); err != nil {
+		return err
+	}
+	defer c.doneWithCommand(cmd)
+	return cmd.Wait()
+}
+
+// combinedOutput is the same as cmd.CombinedOutput, except that the command can
+// be cancelled along with the task we're running it for
+func (c *OSCommand) combinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := c.runCommand(cmd)
+	return output.Bytes(), err
+}
+
+// the most output StreamCommandOutput hands over at a time
+const streamChunkSize = 64 * 1024
+
+// StreamCommandOutput runs a command, handing its output to onOutput a chunk at
+// a time as it comes in rather than waiting for the command to finish, so that
+// we can show the start of e.g. a huge diff straight away. If onOutput returns
+// false we've got all we want, and the command is killed. What the command
+// writes to stderr only goes into the error it fails with
+func (c *OSCommand) StreamCommandOutput(command string, onOutput func(string) bool) error {
+	c.Log.WithField("command", command).Info("StreamCommand")
+	cmd := c.ExecutableFromString(command)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return WrapError(err)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := c.startCommand(cmd); err != nil {
+		return WrapError(err)
+	}
+	defer c.doneWithCommand(cmd)
+
+	stopped := false
+	buffer := make([]byte, streamChunkSize)
+	for {
+		n, readErr := stdout.Read(buffer)
+		if n > 0 && !onOutput(string(buffer[:n])) {
+			stopped = true
+			_ = killProcessGroup(cmd)
+			break
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	// the pipe is closed once the command's been waited on, so we only do that
+	// when we've read everything we want from it
+	err = cmd.Wait()
+	if stopped {
+		return nil
+	}
+	_, err = sanitisedCommandOutput(stderr.Bytes(), err)
+	return err
+}
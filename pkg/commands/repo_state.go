@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// the files and directories in .git that belong to the worktree we're in, as
+// opposed to the repo as a whole, which tell us where HEAD is and whether
+// we're in the middle of a merge or rebase
+var worktreeStateFiles = []string{
+	"HEAD",
+	"ORIG_HEAD",
+	"MERGE_HEAD",
+	"CHERRY_PICK_HEAD",
+	"logs/HEAD",
+	"rebase-merge",
+	"rebase-apply",
+}
+
+// the files and directories in .git that hold the refs and stash, which are
+// shared between worktrees
+var sharedStateFiles = []string{
+	"refs",
+	"packed-refs",
+	"FETCH_HEAD",
+	"logs/refs/stash",
+}
+
+// loose refs are always this small and always the same size, so we go by what's
+// in files up to this size rather than when they were last written to, which
+// can look the same for two writes in quick succession
+const maxFingerprintContentSize = 128
+
+// RefsFingerprint returns a string that changes whenever HEAD, a ref, the stash
+// or the state of a merge or rebase does, going by what's in the files in .git
+// that hold them, or their sizes and modification times for the bigger ones
+// like packed-refs. Comparing it with an earlier one tells us whether e.g. the
+// branch list could have changed, without having to ask git. It's empty if we
+// can't tell, in which case we should assume that everything has changed
+func (c *GitCommand) RefsFingerprint() string {
+	commonDir := c.commonDir()
+	if _, err := os.Stat(filepath.Join(commonDir, "refs")); err != nil {
+		return ""
+	}
+
+	var fingerprint strings.Builder
+	addFiles := func(dir string, names []string) {
+		for _, name := range names {
+			_ = filepath.Walk(filepath.Join(dir, name), func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return nil
+				}
+				if info.Size() <= maxFingerprintContentSize {
+					if content, err := ioutil.ReadFile(path); err == nil {
+						fmt.Fprintf(&fingerprint, "%s %q\n", path, content)
+						return nil
+					}
+				}
+				fmt.Fprintf(&fingerprint, "%s %s\n", path, fileStamp(info))
+				return nil
+			})
+		}
+	}
+	addFiles(c.DotGitDir, worktreeStateFiles)
+	addFiles(commonDir, sharedStateFiles)
+	return fingerprint.String()
+}
+
+// commonDir returns the .git directory of the main worktree, which is where
+// the refs are kept. In a linked worktree our own .git directory points to it
+// with a commondir file
+func (c *GitCommand) commonDir() string {
+	content, err := ioutil.ReadFile(filepath.Join(c.DotGitDir, "commondir"))
+	if err != nil {
+		return c.DotGitDir
+	}
+	commonDir := strings.TrimSpace(string(content))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(c.DotGitDir, commonDir)
+	}
+	return commonDir
+}
+
+// statusState is what we keep track of between runs of git status
+type statusState struct {
+	// indexStamp is the size and modification time the index had when git
+	// status last finished. It's kept so we can tell our own writes to the
+	// index from everybody else's
+	indexMutex sync.Mutex
+	indexStamp string
+
+	cachesOnce sync.Once
+	caches     bool
+}
+
+// IndexChangedSinceStatus tells us whether the index has changed since git
+// status last ran. With the untracked cache or a file system monitor switched
+// on, git status writes to the index itself, and we don't want to take that
+// for a change to the files
+func (c *GitCommand) IndexChangedSinceStatus() bool {
+	c.status.indexMutex.Lock()
+	defer c.status.indexMutex.Unlock()
+	return c.indexStamp() != c.status.indexStamp
+}
+
+func (c *GitCommand) recordStatusIndexStamp() {
+	c.status.indexMutex.Lock()
+	defer c.status.indexMutex.Unlock()
+	c.status.indexStamp = c.indexStamp()
+}
+
+func (c *GitCommand) indexStamp() string {
+	info, err := os.Stat(filepath.Join(c.DotGitDir, "index"))
+	if err != nil {
+		return ""
+	}
+	return fileStamp(info)
+}
+
+func fileStamp(info os.FileInfo) string {
+	return fmt.Sprintf("%d %d", info.Size(), info.ModTime().UnixNano())
+}
@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+}
+
+// TestGitCommandRefsFingerprint is a function.
+func TestGitCommandRefsFingerprint(t *testing.T) {
+	type scenario struct {
+		testName       string
+		setup          func(dotGitDir string)
+		change         func(dotGitDir string)
+		expectedChange bool
+	}
+
+	scenarios := []scenario{
+		{
+			"Nothing changes",
+			func(dotGitDir string) {},
+			func(dotGitDir string) {},
+			false,
+		},
+		{
+			"A branch is updated",
+			func(dotGitDir string) {},
+			func(dotGitDir string) {
+				writeTestFile(t, filepath.Join(dotGitDir, "refs", "heads", "master"), "def\n")
+			},
+			true,
+		},
+		{
+			"A branch is created in a new directory",
+			func(dotGitDir string) {},
+			func(dotGitDir string) {
+				writeTestFile(t, filepath.Join(dotGitDir, "refs", "heads", "feature", "foo"), "abc\n")
+			},
+			true,
+		},
+		{
+			"A rebase starts",
+			func(dotGitDir string) {},
+			func(dotGitDir string) {
+				writeTestFile(t, filepath.Join(dotGitDir, "rebase-merge", "git-rebase-todo"), "pick abc\n")
+			},
+			true,
+		},
+		{
+			"A stash entry is dropped",
+			func(dotGitDir string) {
+				writeTestFile(t, filepath.Join(dotGitDir, "logs", "refs", "stash"), "one\ntwo\n")
+			},
+			func(dotGitDir string) {
+				writeTestFile(t, filepath.Join(dotGitDir, "logs", "refs", "stash"), "one\n")
+			},
+			true,
+		},
+		{
+			"The index changes",
+			func(dotGitDir string) {},
+			func(dotGitDir string) {
+				writeTestFile(t, filepath.Join(dotGitDir, "index"), "index")
+			},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			dotGitDir, err := ioutil.TempDir("", "lazygit-test")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dotGitDir)
+			writeTestFile(t, filepath.Join(dotGitDir, "HEAD"), "ref: refs/heads/master\n")
+			writeTestFile(t, filepath.Join(dotGitDir, "refs", "heads", "master"), "abc\n")
+			s.setup(dotGitDir)
+
+			gitCmd := NewDummyGitCommand()
+			gitCmd.DotGitDir = dotGitDir
+			before := gitCmd.RefsFingerprint()
+			assert.NotEqual(t, "", before)
+
+			s.change(dotGitDir)
+			assert.EqualValues(t, s.expectedChange, gitCmd.RefsFingerprint() != before)
+		})
+	}
+}
+
+// TestGitCommandRefsFingerprintWorktree is a function.
+func TestGitCommandRefsFingerprintWorktree(t *testing.T) {
+	commonDir, err := ioutil.TempDir("", "lazygit-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(commonDir)
+	dotGitDir := filepath.Join(commonDir, "worktrees", "other")
+	writeTestFile(t, filepath.Join(dotGitDir, "HEAD"), "ref: refs/heads/other\n")
+	writeTestFile(t, filepath.Join(dotGitDir, "commondir"), "../..\n")
+	writeTestFile(t, filepath.Join(commonDir, "refs", "heads", "other"), "abc\n")
+
+	gitCmd := NewDummyGitCommand()
+	gitCmd.DotGitDir = dotGitDir
+	before := gitCmd.RefsFingerprint()
+	assert.NotEqual(t, "", before)
+
+	writeTestFile(t, filepath.Join(commonDir, "refs", "heads", "other"), "defg\n")
+	assert.NotEqual(t, before, gitCmd.RefsFingerprint())
+
+	// without the refs we can't tell what's changed
+	assert.NoError(t, os.Remove(filepath.Join(dotGitDir, "commondir")))
+	assert.EqualValues(t, "", gitCmd.RefsFingerprint())
+}
+
+// TestGitCommandIndexChangedSinceStatus is a function.
+func TestGitCommandIndexChangedSinceStatus(t *testing.T) {
+	type scenario struct {
+		testName        string
+		untrackedCache  string
+		expectedChanged bool
+	}
+
+	scenarios := []scenario{
+		{
+			// git status can't write to the index, so it's somebody else
+			"Index written to while git status runs",
+			"",
+			true,
+		},
+		{
+			"Index written to by git status",
+			"true",
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			dotGitDir, err := ioutil.TempDir("", "lazygit-test")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dotGitDir)
+			indexPath := filepath.Join(dotGitDir, "index")
+			writeTestFile(t, indexPath, "index")
+
+			gitCmd := NewDummyGitCommand()
+			gitCmd.DotGitDir = dotGitDir
+			gitCmd.getLocalGitConfig = func(key string) (string, error) {
+				if key == "core.untrackedCache" {
+					return s.untrackedCache, nil
+				}
+				return "", nil
+			}
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("sh", "-c", "printf 'refreshed index' > "+indexPath)
+			}
+			assert.True(t, gitCmd.IndexChangedSinceStatus())
+
+			_, err = gitCmd.GitStatus("all")
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectedChanged, gitCmd.IndexChangedSinceStatus())
+
+			later := time.Now().Add(time.Minute)
+			assert.NoError(t, os.Chtimes(indexPath, later, later))
+			assert.True(t, gitCmd.IndexChangedSinceStatus())
+		})
+	}
+}
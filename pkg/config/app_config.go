@@ -38,6 +38,7 @@ type AppConfigurer interface {
 	GetUserConfigDir() string
 	GetAppState() *AppState
 	WriteToUserConfig(string, string) error
+	LoadRepoConfig(string) error
 	SaveAppState() error
 	LoadAppState() error
 	SetIsNewRepo(bool)
@@ -51,6 +52,10 @@ func NewAppConfig(name, version, commit, date string, buildSource string, debugg
 		return nil, err
 	}
 
+	if err := loadTheme(userConfig, filepath.Dir(userConfigPath)); err != nil {
+		return nil, err
+	}
+
 	if os.Getenv("DEBUG") == "TRUE" {
 		debuggingFlag = true
 	}
@@ -204,6 +209,62 @@ func (c *AppConfig) WriteToUserConfig(key, value string) error {
 	return v.WriteConfig()
 }
 
+// LoadRepoConfig reloads the user config and merges in the config of the repo
+// whose .git directory is given, found at .git/lazygit.yml, so that settings
+// like commit message rules can be set per repo. We reload first so that
+// settings from a previously opened repo don't leak into this one
+func (c *AppConfig) LoadRepoConfig(dotGitDir string) error {
+	userConfig, _, err := LoadConfig("config", true)
+	if err != nil {
+		return err
+	}
+
+	repoConfig, err := ioutil.ReadFile(filepath.Join(dotGitDir, "lazygit.yml"))
+	if err == nil {
+		if err := userConfig.MergeConfig(bytes.NewBuffer(repoConfig)); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := loadTheme(userConfig, c.UserConfigDir); err != nil {
+		return err
+	}
+
+	c.UserConfig = userConfig
+	return nil
+}
+
+// loadTheme merges in the theme file named by gui.theme.name, which lives in
+// the themes folder of the config directory e.g. themes/solarized.yml. It has
+// the same settings as gui.theme, and takes precedence over them
+func loadTheme(v *viper.Viper, configDir string) error {
+	name := v.GetString("gui.theme.name")
+	if name == "" {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(configDir, "themes", name+".yml"))
+	if err != nil {
+		return err
+	}
+	themeSettings := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &themeSettings); err != nil {
+		return err
+	}
+	// viper can only merge in a whole config, so we nest the theme under gui.theme
+	themeConfig, err := yaml.Marshal(map[string]interface{}{
+		"gui": map[string]interface{}{
+			"theme": themeSettings,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return v.MergeConfig(bytes.NewBuffer(themeConfig))
+}
+
 // SaveAppState marshalls the AppState struct and writes it to the disk
 func (c *AppConfig) SaveAppState() error {
 	marshalledAppState, err := yaml.Marshal(c.AppState)
@@ -242,9 +303,12 @@ func GetDefaultConfig() []byte {
   ## stuff relating to the UI
   scrollHeight: 2
   scrollPastBottom: true
-  mouseEvents: false # will default to true when the feature is complete
+  mouseEvents: true
+  portraitMode: auto # one of: auto | always | never
+  showCommandLog: false
   theme:
-    lightTheme: false
+    name: '' # a theme file in the themes folder of the config directory, without the .yml
+    lightTheme: auto # one of: true | false | auto
     activeBorderColor:
       - white
       - bold
@@ -252,6 +316,46 @@ func GetDefaultConfig() []byte {
       - white
     optionsTextColor:
       - blue
+    selectedLineBgColor:
+      - default
+    commitStatusColors:
+      unpushed:
+        - red
+      pushed:
+        - yellow
+      merged:
+        - green
+      rebasing:
+        - blue
+      selected:
+        - magenta
+    cherryPickedCommitFgColor:
+      - cyan
+    cherryPickedCommitBgColor:
+      - blue
+    rebaseActionColor:
+      - cyan
+    branchColors:
+      feature:
+        - green
+      bugfix:
+        - yellow
+      hotfix:
+        - red
+    stagedColor:
+      - green
+    unstagedColor:
+      - red
+    conflictMarkerColor:
+      - red
+    selectedConflictColor:
+      - bold
+    searchMatchColor:
+      - reverse
+    selectedRangeColor:
+      - reverse
+    syntaxStyle: default # one of: default | monokai | monochrome | none
+    syntaxColors: {} # e.g. keyword: [blue, bold], for each of keyword, string, comment and number
   commitLength:
     show: true
 git:
@@ -259,11 +363,149 @@ git:
     manualCommit: false
   skipHookPrefix: 'WIP'
   autoFetch: true
+  commitMessage:
+    maxSubjectLength: 0 # 0 means no limit
+    subjectPattern: ''
+    noTrailingPeriod: false
+  commitPrefix:
+    pattern: ''
+    replace: ''
+  trailers:
+    keys: []
+    people: []
+  paging:
+    pager: ''
+  untrackedFiles: all # can be: all | lazy | hidden
+  maxDiffSize: 10 # megabytes, 0 means no limit
+refresher:
+  watchFiles: true
+  maxWatchedDirectories: 5000
+  pollInterval: 10 # seconds
 update:
   method: prompt # can be: prompt | background | never
   days: 14 # how often a update is checked for
 reporting: 'undetermined' # one of: 'on' | 'off' | 'undetermined'
 confirmOnQuit: false
+customCommands: []
+keybinding:
+  universal:
+    quit: 'q'
+    quit-alt1: '<c-c>' # alternative/alias of quit
+    return: '<esc>' # return to previous menu, will quit if there's nowhere to return
+    togglePanel: '<tab>' # goto the next panel
+    prevItem: '<up>' # go one line up
+    nextItem: '<down>' # go one line down
+    prevItem-alt: 'k' # go one line up
+    nextItem-alt: 'j' # go one line down
+    prevBlock: '<left>' # goto the previous block / panel
+    nextBlock: '<right>' # goto next block / panel
+    prevBlock-alt: 'h' # goto the previous block / panel
+    nextBlock-alt: 'l' # goto next block / panel
+    goToStatus: '1'
+    goToFiles: '2'
+    goToBranches: '3'
+    goToCommits: '4'
+    goToStash: '5'
+    optionMenu: 'x' # show help menu
+    select: '<space>'
+    confirm: '<enter>'
+    scrollUpMain: '<pgup>' # main panel scroll up
+    scrollDownMain: '<pgdown>' # main panel scroll down
+    scrollUpMain-alt1: 'K' # main panel scroll up
+    scrollDownMain-alt1: 'J' # main panel scroll down
+    scrollUpMain-alt2: '<c-u>' # main panel scroll up
+    scrollDownMain-alt2: '<c-d>' # main panel scroll down
+    createRebaseOptionsMenu: 'm'
+    pushFiles: 'P'
+    createPushMenu: '>'
+    pullFiles: 'p'
+    createPullMenu: '<'
+    refresh: 'R'
+    toggleSideBySideDiff: '|' # show diffs as two columns, old on the left and new on the right
+    diffOptions: 'W' # context lines, whitespace, word diff and rename detection
+    nextScreenMode: '+' # normal, half and fullscreen for the focused panel
+    prevScreenMode: '_'
+    toggleCommandLog: '@'
+    focusCommandLog: '~'
+    cancelCommand: '<c-x>' # kill the commands running in the background
+    startSearch: '/' # search the focused panel
+    startSearchMain: '?' # search the main panel
+    nextMatch: 'n'
+    prevMatch: 'N'
+    filter: '<c-f>' # narrow the focused list or menu down to the items that fuzzy match what you type
+  status:
+    editConfig: 'e'
+    openConfig: 'o'
+    checkForUpdate: 'u'
+    recentRepos: 's'
+  files:
+    commitChanges: 'c'
+    commitChangesWithoutHook: 'w' # commit changes without pre-commit hook
+    amendLastCommit: 'A'
+    commitChangesWithEditor: 'C'
+    toggleStaged: '<space>'
+    toggleStagedAll: 'a'
+    viewDiscardOptions: 'd'
+    viewResetOptions: 'D'
+    viewStashOptions: 'S'
+    stashAllChanges: 's'
+    edit: 'e'
+    openFile: 'o'
+    ignoreFile: 'i'
+    refreshFiles: 'r'
+    addPatch: 't'
+    goInto: '<enter>'
+    fetch: 'f'
+    executeCustomCommand: 'X'
+  branches:
+    checkoutBranch: '<space>'
+    checkoutBranchByName: 'c'
+    forceCheckoutBranch: 'F'
+    newBranch: 'n'
+    deleteBranch: 'd'
+    rebaseBranch: 'r'
+    mergeIntoCurrentBranch: 'M'
+    fastForward: 'f' # fast-forward this branch from its upstream
+    createPullRequest: 'o'
+  commits:
+    squashDown: 's'
+    renameCommit: 'r'
+    renameCommitWithEditor: 'R'
+    viewResetOptions: 'g'
+    markCommitAsFixup: 'f'
+    createFixupCommit: 'F' # create fixup commit for this commit
+    squashAboveCommits: 'S'
+    deleteCommit: 'd'
+    moveDownCommit: '<c-j>' # move commit down one
+    moveUpCommit: '<c-k>' # move commit up one
+    editCommit: 'e'
+    amendToCommit: 'A'
+    pickCommit: 'p' # pick commit (when mid-rebase)
+    revertCommit: 't'
+    cherryPickCopy: 'c'
+    cherryPickCopyRange: 'C'
+    pasteCommits: 'v'
+    viewCommitFiles: '<enter>'
+    toggleDiffCommit: '<space>'
+  stash:
+    applyStash: '<space>'
+    popStash: 'g'
+    dropStash: 'd'
+  commitFiles:
+    checkoutCommitFile: 'c'
+    discardOldFileChange: 'd'
+    openFile: 'o'
+  commitMessage:
+    newLine: '<enter>' # in the description
+    confirm: '<c-s>' # in the description
+    addTrailer: '<c-t>'
+    toggleSkipHooks: '<c-n>'
+  main:
+    stageLine: '<space>'
+    stageHunk: 'a'
+    pickHunk: '<space>'
+    pickBothHunks: 'b'
+    undo: 'z'
 `)
 }
 
@@ -272,6 +514,63 @@ confirmOnQuit: false
 type AppState struct {
 	LastUpdateCheck int64
 	RecentRepos     []string
+	RepoStates      map[string]*RepoState
+}
+
+// RepoState stores data between runs of the app that only applies to a single
+// repo, like how diverged branches should be reconciled when pulling
+type RepoState struct {
+	PullMode             string
+	CommitMessageDraft   string
+	CommitMessageHistory []string
+	DiffOptions          *DiffOptions
+}
+
+// DefaultDiffContextLines is how many lines of context git shows around a
+// change unless told otherwise
+const DefaultDiffContextLines = 3
+
+// DiffOptions are the arguments we pass to git when showing diffs, which the
+// user can change at runtime
+type DiffOptions struct {
+	ContextLines     int
+	IgnoreWhitespace bool
+	WordDiff         bool
+	// similarity percentages for -M and -C, where 0 leaves detection up to git
+	RenameThreshold int
+	CopyThreshold   int
+}
+
+// the number of commit messages we remember per repo
+const commitMessageHistoryLimit = 50
+
+// AddToCommitMessageHistory records a commit message as the most recently used
+// one, moving it to the front if it was used before
+func (r *RepoState) AddToCommitMessageHistory(message string) {
+	history := []string{message}
+	for _, existing := range r.CommitMessageHistory {
+		if existing != message && len(history) < commitMessageHistoryLimit {
+			history = append(history, existing)
+		}
+	}
+	r.CommitMessageHistory = history
+}
+
+// GetRepoState returns the stored state for the repo at the given path,
+// creating an empty one if we have not stored anything for that repo yet
+func (a *AppState) GetRepoState(repoPath string) *RepoState {
+	if a.RepoStates == nil {
+		a.RepoStates = map[string]*RepoState{}
+	}
+	repoState, ok := a.RepoStates[repoPath]
+	if !ok {
+		repoState = &RepoState{}
+		a.RepoStates[repoPath] = repoState
+	}
+	if repoState.DiffOptions == nil {
+		repoState.DiffOptions = &DiffOptions{ContextLines: DefaultDiffContextLines}
+	}
+	return repoState
 }
 
 func getDefaultAppState() []byte {
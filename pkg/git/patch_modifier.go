@@ -62,6 +62,13 @@ func (p *PatchModifier) getHeaderLength(patchLines []string) (int, error) {
 // ModifyPatchForLine takes the original patch, which may contain several hunks,
 // and the line number of the line we want to stage
 func (p *PatchModifier) ModifyPatchForLine(patch string, lineNumber int) (string, error) {
+	return p.ModifyPatchForRange(patch, lineNumber, lineNumber)
+}
+
+// ModifyPatchForRange takes the original patch, which may contain several
+// hunks, and the line numbers of the first and last lines of a range we want
+// to stage. The range can span several hunks, which are all included
+func (p *PatchModifier) ModifyPatchForRange(patch string, firstLineNumber int, lastLineNumber int) (string, error) {
 	lines := strings.Split(patch, "\n")
 	headerLength, err := p.getHeaderLength(lines)
 	if err != nil {
@@ -69,17 +76,26 @@ func (p *PatchModifier) ModifyPatchForLine(patch string, lineNumber int) (string
 	}
 	output := strings.Join(lines[0:headerLength], "\n") + "\n"
 
-	hunkStart, err := p.getHunkStart(lines, lineNumber)
+	hunkStart, err := p.getHunkStart(lines, firstLineNumber)
 	if err != nil {
 		return "", err
 	}
 
-	hunk, err := p.getModifiedHunk(lines, hunkStart, lineNumber)
-	if err != nil {
-		return "", err
+	hunks := []string{}
+	for hunkStart != -1 && hunkStart <= lastLineNumber {
+		hunk, nextHunkStart, err := p.getModifiedHunk(lines, hunkStart, firstLineNumber, lastLineNumber)
+		if err != nil {
+			return "", err
+		}
+		if nextHunkStart != -1 && nextHunkStart <= lastLineNumber {
+			// another hunk follows, so we drop the blank line that ends this one
+			hunk = hunk[:len(hunk)-1]
+		}
+		hunks = append(hunks, strings.Join(hunk, "\n"))
+		hunkStart = nextHunkStart
 	}
 
-	output += strings.Join(hunk, "\n")
+	output += strings.Join(hunks, "\n")
 
 	return output, nil
 }
@@ -101,17 +117,22 @@ func (p *PatchModifier) getHunkStart(patchLines []string, lineNumber int) (int,
 	return 0, errors.New(p.Tr.SLocalize("CantFindHunk"))
 }
 
-func (p *PatchModifier) getModifiedHunk(patchLines []string, hunkStart int, lineNumber int) ([]string, error) {
+// getModifiedHunk strips the hunk starting at hunkStart down to the lines in
+// the range we want to stage, returning it along with where the next hunk
+// starts, or -1 if it's the last one
+func (p *PatchModifier) getModifiedHunk(patchLines []string, hunkStart int, firstLineNumber int, lastLineNumber int) ([]string, int, error) {
 	lineChanges := 0
-	// strip the hunk down to just the line we want to stage
+	nextHunkStart := -1
+	// strip the hunk down to just the lines we want to stage
 	newHunk := []string{patchLines[hunkStart]}
 	for offsetIndex, line := range patchLines[hunkStart+1:] {
 		index := offsetIndex + hunkStart + 1
 		if strings.HasPrefix(line, "@@") {
 			newHunk = append(newHunk, "\n")
+			nextHunkStart = index
 			break
 		}
-		if index != lineNumber {
+		if index < firstLineNumber || index > lastLineNumber {
 			// we include other removals but treat them like context
 			if strings.HasPrefix(line, "-") {
 				newHunk = append(newHunk, " "+line[1:])
@@ -130,10 +151,10 @@ func (p *PatchModifier) getModifiedHunk(patchLines []string, hunkStart int, line
 	var err error
 	newHunk[0], err = p.updatedHeader(newHunk[0], lineChanges)
 	if err != nil {
-		return nil, err
+		return nil, -1, err
 	}
 
-	return newHunk, nil
+	return newHunk, nextHunkStart, nil
 }
 
 // updatedHeader returns the hunk header with the updated line range
@@ -83,3 +83,19 @@ func TestModifyPatchForLine(t *testing.T) {
 		})
 	}
 }
+
+func TestModifyPatchForRange(t *testing.T) {
+	p := NewDummyPatchModifier()
+	beforePatch, err := ioutil.ReadFile("testdata/testPatchBefore2.diff")
+	if err != nil {
+		panic("Cannot open file at testdata/testPatchBefore2.diff")
+	}
+	// from the last removal of the first hunk to part way through the second
+	afterPatch, err := p.ModifyPatchForRange(string(beforePatch), 33, 48)
+	assert.NoError(t, err)
+	expected, err := ioutil.ReadFile("testdata/testPatchAfter5.diff")
+	if err != nil {
+		panic("Cannot open file at testdata/testPatchAfter5.diff")
+	}
+	assert.Equal(t, string(expected), afterPatch)
+}
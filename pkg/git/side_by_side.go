@@ -0,0 +1,241 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+	"github.com/mattn/go-runewidth"
+)
+
+// the narrowest view we'll bother splitting into two columns
+const minSideBySideWidth = 40
+
+var hunkHeaderRegexp = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// sideBySideCell is one half of a row: a line number and the line's text,
+// with the part that differs from the other side of the row marked out
+type sideBySideCell struct {
+	lineNumber int
+	text       []rune
+	// the runes from changeStart up to changeEnd are highlighted
+	changeStart int
+	changeEnd   int
+	// the colour of a removed or added line, left unset for context lines
+	colour color.Attribute
+}
+
+// sideBySideBuilder lays a unified diff out as two columns, the old version of
+// each hunk on the left and the new one on the right
+type sideBySideBuilder struct {
+	output      []string
+	removed     []string
+	added       []string
+	oldLine     int
+	newLine     int
+	gutterWidth int
+	leftWidth   int
+	rightWidth  int
+}
+
+// SideBySideDiff takes a diff as output by git, with or without colour, and
+// lays it out in two columns to fit the given width. Removed lines are
+// paired up with the added lines that replaced them, and the part of a pair
+// that actually changed is highlighted. Anything outside of a hunk, like
+// commit messages and file headers, is left as it was. If the diff has no
+// hunks to lay out (e.g. because a pager has already formatted it) or the
+// width is too narrow, it's returned untouched
+func SideBySideDiff(diff string, width int) string {
+	if width < minSideBySideWidth {
+		return diff
+	}
+
+	lines := strings.Split(diff, "\n")
+	plainLines := strings.Split(utils.Decolorise(diff), "\n")
+
+	gutterWidth := 0
+	for _, line := range plainLines {
+		if end := hunkEnd(line); end > 0 {
+			gutterWidth = utils.Max(gutterWidth, len(strconv.Itoa(end)))
+		}
+	}
+	if gutterWidth == 0 {
+		return diff
+	}
+
+	// one column goes to the divider between the two sides
+	leftWidth := (width - 1) / 2
+	b := &sideBySideBuilder{
+		gutterWidth: gutterWidth,
+		leftWidth:   leftWidth,
+		rightWidth:  width - 1 - leftWidth,
+	}
+
+	inHunk := false
+	for i, line := range plainLines {
+		if match := hunkHeaderRegexp.FindStringSubmatch(line); match != nil {
+			b.flush()
+			b.oldLine, _ = strconv.Atoi(match[1])
+			b.newLine, _ = strconv.Atoi(match[3])
+			b.output = append(b.output, lines[i])
+			inHunk = true
+			continue
+		}
+
+		if inHunk {
+			switch {
+			case strings.HasPrefix(line, "-"):
+				b.removed = append(b.removed, line[1:])
+				continue
+			case strings.HasPrefix(line, "+"):
+				b.added = append(b.added, line[1:])
+				continue
+			case strings.HasPrefix(line, " "):
+				b.flush()
+				b.addContextLine(line[1:])
+				continue
+			case strings.HasPrefix(line, `\`):
+				// '\ No newline at end of file'
+				b.flush()
+				b.output = append(b.output, lines[i])
+				continue
+			}
+		}
+
+		b.flush()
+		inHunk = false
+		b.output = append(b.output, lines[i])
+	}
+	b.flush()
+
+	return strings.Join(b.output, "\n")
+}
+
+// hunkEnd returns the highest line number that a hunk header covers on
+// either side, or 0 if the line isn't a hunk header
+func hunkEnd(line string) int {
+	match := hunkHeaderRegexp.FindStringSubmatch(line)
+	if match == nil {
+		return 0
+	}
+	end := 0
+	for _, pair := range [][]string{match[1:3], match[3:5]} {
+		start, _ := strconv.Atoi(pair[0])
+		count := 1
+		if pair[1] != "" {
+			count, _ = strconv.Atoi(pair[1])
+		}
+		end = utils.Max(end, start+count)
+	}
+	return end
+}
+
+func (b *sideBySideBuilder) addContextLine(text string) {
+	runes := expandTabs(text)
+	b.addRow(
+		&sideBySideCell{lineNumber: b.oldLine, text: runes},
+		&sideBySideCell{lineNumber: b.newLine, text: runes},
+	)
+	b.oldLine++
+	b.newLine++
+}
+
+// flush lays out the run of removed and added lines we've been holding onto,
+// pairing them up row by row so that a changed line sits next to its
+// replacement
+func (b *sideBySideBuilder) flush() {
+	for i := 0; i < len(b.removed) || i < len(b.added); i++ {
+		var left, right *sideBySideCell
+		if i < len(b.removed) {
+			left = &sideBySideCell{lineNumber: b.oldLine, text: expandTabs(b.removed[i]), colour: color.FgRed}
+			b.oldLine++
+		}
+		if i < len(b.added) {
+			right = &sideBySideCell{lineNumber: b.newLine, text: expandTabs(b.added[i]), colour: color.FgGreen}
+			b.newLine++
+		}
+		if left != nil && right != nil {
+			markChange(left, right)
+		}
+		b.addRow(left, right)
+	}
+
+	b.removed = nil
+	b.added = nil
+}
+
+// markChange finds where two lines stop having the same start and end, which
+// is the part we highlight. Lines with nothing in common are left alone,
+// seeing as highlighting all of both wouldn't tell you anything
+func markChange(left, right *sideBySideCell) {
+	prefix := 0
+	for prefix < len(left.text) && prefix < len(right.text) && left.text[prefix] == right.text[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(left.text)-prefix && suffix < len(right.text)-prefix &&
+		left.text[len(left.text)-1-suffix] == right.text[len(right.text)-1-suffix] {
+		suffix++
+	}
+	if prefix == 0 && suffix == 0 {
+		return
+	}
+
+	left.changeStart, left.changeEnd = prefix, len(left.text)-suffix
+	right.changeStart, right.changeEnd = prefix, len(right.text)-suffix
+}
+
+func (b *sideBySideBuilder) addRow(left, right *sideBySideCell) {
+	b.output = append(b.output, b.renderCell(left, b.leftWidth)+"│"+b.renderCell(right, b.rightWidth))
+}
+
+// renderCell renders one side of a row, cutting the line short if it doesn't
+// fit and padding it out if it does so that the divider lines up
+func (b *sideBySideBuilder) renderCell(cell *sideBySideCell, width int) string {
+	if cell == nil {
+		return strings.Repeat(" ", width)
+	}
+
+	gutter := fmt.Sprintf("%*d ", b.gutterWidth, cell.lineNumber)
+	textWidth := width - len(gutter)
+
+	used := 0
+	end := 0
+	for end < len(cell.text) && used+runewidth.RuneWidth(cell.text[end]) <= textWidth {
+		used += runewidth.RuneWidth(cell.text[end])
+		end++
+	}
+	text := cell.text[:end]
+	padding := strings.Repeat(" ", utils.Max(textWidth-used, 0))
+
+	if cell.colour == 0 {
+		return gutter + string(text) + padding
+	}
+
+	changeStart := utils.Min(cell.changeStart, len(text))
+	changeEnd := utils.Min(utils.Max(cell.changeEnd, changeStart), len(text))
+	segments := []struct {
+		text  []rune
+		style *color.Color
+	}{
+		{text[:changeStart], color.New(cell.colour)},
+		{text[changeStart:changeEnd], color.New(cell.colour, color.ReverseVideo)},
+		{text[changeEnd:], color.New(cell.colour)},
+	}
+
+	output := gutter
+	for _, segment := range segments {
+		if len(segment.text) > 0 {
+			output += segment.style.Sprint(string(segment.text))
+		}
+	}
+	return output + padding
+}
+
+// expandTabs swaps tabs for spaces so that we know how wide a line really is
+func expandTabs(text string) []rune {
+	return []rune(strings.Replace(text, "\t", "    ", -1))
+}
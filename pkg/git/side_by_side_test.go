@@ -0,0 +1,87 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSideBySideDiff(t *testing.T) {
+	type scenario struct {
+		testName string
+		diff     string
+		width    int
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Changed line sits next to its replacement",
+			strings.Join([]string{
+				"diff --git a/a.txt b/a.txt",
+				"@@ -9,3 +9,3 @@ func",
+				" same",
+				"-old value",
+				"+new value",
+				" same",
+			}, "\n"),
+			41,
+			[]string{
+				"diff --git a/a.txt b/a.txt",
+				"@@ -9,3 +9,3 @@ func",
+				" 9 same             │ 9 same             ",
+				"10 \x1b[31;7mold\x1b[0m\x1b[31m value\x1b[0m        │10 \x1b[32;7mnew\x1b[0m\x1b[32m value\x1b[0m        ",
+				"11 same             │11 same             ",
+			},
+		},
+		{
+			"Uneven runs of removed and added lines",
+			strings.Join([]string{
+				"@@ -1,2 +1,1 @@",
+				"-one",
+				"-two",
+				"+six",
+			}, "\n"),
+			41,
+			[]string{
+				"@@ -1,2 +1,1 @@",
+				"1 \x1b[31mone\x1b[0m               │1 \x1b[32msix\x1b[0m               ",
+				"2 \x1b[31mtwo\x1b[0m               │                    ",
+			},
+		},
+		{
+			"Long lines are cut short",
+			strings.Join([]string{
+				"@@ -1 +1 @@",
+				" this line is far too long to fit",
+			}, "\n"),
+			41,
+			[]string{
+				"@@ -1 +1 @@",
+				"1 this line is far t│1 this line is far t",
+			},
+		},
+		{
+			"Diff without hunks",
+			"some pager's output",
+			41,
+			[]string{"some pager's output"},
+		},
+		{
+			"Too narrow to split",
+			"@@ -1 +1 @@\n same",
+			39,
+			[]string{"@@ -1 +1 @@", " same"},
+		},
+	}
+
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	color.NoColor = false
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, strings.Split(SideBySideDiff(s.diff, s.width), "\n"))
+		})
+	}
+}
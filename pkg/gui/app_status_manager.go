@@ -1,21 +1,37 @@
 package gui
 
 import (
+	"strings"
+	"sync"
+
 	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
 type appStatus struct {
+	id         int
 	name       string
 	statusType string
 	duration   int
+	// task is what's running the commands a waiting status is waiting on,
+	// and cancelled is set once the user has cancelled them
+	task      *commands.Task
+	cancelled bool
 }
 
+// statusManager holds the statuses shown at the bottom left of the screen,
+// which include the tasks running in the background. Tasks are added and
+// removed from their own goroutines, hence the mutex
 type statusManager struct {
+	mutex    sync.Mutex
 	statuses []appStatus
+	nextID   int
 }
 
 func (m *statusManager) removeStatus(name string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	newStatuses := []appStatus{}
 	for _, status := range m.statuses {
 		if status.name != name {
@@ -25,42 +41,109 @@ func (m *statusManager) removeStatus(name string) {
 	m.statuses = newStatuses
 }
 
-func (m *statusManager) addWaitingStatus(name string) {
-	m.removeStatus(name)
+func (m *statusManager) removeStatusByID(id int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	newStatuses := []appStatus{}
+	for _, status := range m.statuses {
+		if status.id != id {
+			newStatuses = append(newStatuses, status)
+		}
+	}
+	m.statuses = newStatuses
+}
+
+// addWaitingStatus adds a status that's shown with a spinner until it's
+// removed, returning its id. Several of these can share a name when the same
+// kind of task is running more than once
+func (m *statusManager) addWaitingStatus(name string) int {
+	return m.addTaskStatus(name, nil)
+}
+
+// addTaskStatus adds a waiting status for a task, whose commands are killed if
+// the user cancels it
+func (m *statusManager) addTaskStatus(name string, task *commands.Task) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.nextID++
 	newStatus := appStatus{
+		id:         m.nextID,
 		name:       name,
 		statusType: "waiting",
 		duration:   0,
+		task:       task,
 	}
 	m.statuses = append([]appStatus{newStatus}, m.statuses...)
+	return newStatus.id
 }
 
+// cancelTasks marks every waiting status with a task as cancelled, killing
+// the task's commands
+func (m *statusManager) cancelTasks() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for i := range m.statuses {
+		if m.statuses[i].task != nil {
+			m.statuses[i].cancelled = true
+			m.statuses[i].task.Kill()
+		}
+	}
+}
+
+func (m *statusManager) isCancelled(id int) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, status := range m.statuses {
+		if status.id == id {
+			return status.cancelled
+		}
+	}
+	return false
+}
+
+// getStatusString lists the names of the tasks that are running, newest first,
+// or shows the latest status if there are none
 func (m *statusManager) getStatusString() string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	if len(m.statuses) == 0 {
 		return ""
 	}
-	topStatus := m.statuses[0]
-	if topStatus.statusType == "waiting" {
-		return topStatus.name + " " + utils.Loader()
+	names := []string{}
+	for _, status := range m.statuses {
+		if status.statusType == "waiting" {
+			names = append(names, status.name)
+		}
+	}
+	if len(names) > 0 {
+		return strings.Join(names, ", ") + " " + utils.Loader()
 	}
-	return topStatus.name
+	return m.statuses[0].name
 }
 
-// WithWaitingStatus wraps a function and shows a waiting status while the function is still executing
-func (gui *Gui) WithWaitingStatus(name string, f func() error) error {
-	go func() {
-		gui.g.Update(func(g *gocui.Gui) error {
-			gui.statusManager.addWaitingStatus(name)
-			return nil
-		})
+// runTask runs a function as a task, which is listed in the app status while
+// it runs. The commands the function runs through the GitCommand it's handed
+// can be cancelled with the cancelCommand key. It returns whether the task was
+// cancelled along with the function's error
+func (gui *Gui) runTask(name string, f func(git *commands.GitCommand) error) (bool, error) {
+	task := commands.NewTask()
+	id := gui.statusManager.addTaskStatus(name, task)
+	defer gui.statusManager.removeStatusByID(id)
 
-		defer gui.g.Update(func(g *gocui.Gui) error {
-			gui.statusManager.removeStatus(name)
-			return nil
-		})
+	err := f(gui.GitCommand.WithTask(task))
+	return gui.statusManager.isCancelled(id), err
+}
 
-		if err := f(); err != nil {
+// WithWaitingStatus runs a function as a task on a background worker. If the
+// function fails the error is shown, unless the user has cancelled the task
+func (gui *Gui) WithWaitingStatus(name string, f func(git *commands.GitCommand) error) error {
+	go func() {
+		cancelled, err := gui.runTask(name, f)
+		if err != nil {
 			gui.g.Update(func(g *gocui.Gui) error {
+				if cancelled {
+					return gui.onTaskCancelled()
+				}
 				return gui.createErrorPanel(gui.g, err.Error())
 			})
 		}
@@ -68,3 +151,32 @@ func (gui *Gui) WithWaitingStatus(name string, f func() error) error {
 
 	return nil
 }
+
+// handleCancelCommands kills the commands that the tasks running in the
+// background are waiting on, along with whatever they've started, e.g. a push
+// that's hanging on a slow pre-push hook. The loads that refresh the panels
+// are left alone
+func (gui *Gui) handleCancelCommands(g *gocui.Gui, v *gocui.View) error {
+	gui.statusManager.cancelTasks()
+	return nil
+}
+
+// onTaskCancelled brings the panels back in line with the repo after a task
+// has been cancelled, as it may have been stopped half way through
+func (gui *Gui) onTaskCancelled() error {
+	return gui.refreshSidePanels(gui.g)
+}
+
+// closeCancelledLoader closes the loader panel, and the credentials panel if
+// it was opened, of a task that's been cancelled
+func (gui *Gui) closeCancelledLoader(credentialsOpened bool) {
+	gui.g.Update(func(g *gocui.Gui) error {
+		if credentialsOpened {
+			_, _ = g.SetViewOnBottom("credentials")
+		}
+		if err := gui.closeConfirmationPrompt(g); err != nil {
+			return err
+		}
+		return gui.onTaskCancelled()
+	})
+}
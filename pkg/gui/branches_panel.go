@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/commands"
 	"github.com/jesseduffield/lazygit/pkg/git"
@@ -66,19 +67,22 @@ func (gui *Gui) RenderSelectedBranchUpstreamDifferences() error {
 // gui.refreshStatus is called at the end of this because that's when we can
 // be sure there is a state.Branches array to pick the current branch from
 func (gui *Gui) refreshBranches(g *gocui.Gui) error {
-	g.Update(func(g *gocui.Gui) error {
+	gui.loadCachedInBackground("branches", gui.GitCommand.RefsFingerprint, func() (func(*gocui.Gui) error, error) {
 		builder, err := git.NewBranchListBuilder(gui.Log, gui.GitCommand)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		gui.State.Branches = builder.Build()
+		branches := builder.Build()
+		return func(g *gocui.Gui) error {
+			gui.State.Branches = branches
 
-		gui.refreshSelectedLine(&gui.State.Panels.Branches.SelectedLine, len(gui.State.Branches))
-		if err := gui.RenderSelectedBranchUpstreamDifferences(); err != nil {
-			return err
-		}
+			gui.refreshSelectedLine(&gui.State.Panels.Branches.SelectedLine, len(gui.State.Branches))
+			if err := gui.RenderSelectedBranchUpstreamDifferences(); err != nil {
+				return err
+			}
 
-		return gui.refreshStatus(g)
+			return gui.refreshStatus(g)
+		}, nil
 	})
 	return nil
 }
@@ -89,7 +93,7 @@ func (gui *Gui) handleBranchesNextLine(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	panelState := gui.State.Panels.Branches
-	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.Branches), false)
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, len(gui.State.Branches), false)
 
 	if err := gui.resetOrigin(gui.getMainView()); err != nil {
 		return err
@@ -103,7 +107,7 @@ func (gui *Gui) handleBranchesPrevLine(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	panelState := gui.State.Panels.Branches
-	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.Branches), true)
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, len(gui.State.Branches), true)
 
 	if err := gui.resetOrigin(gui.getMainView()); err != nil {
 		return err
@@ -140,7 +144,16 @@ func (gui *Gui) handleGitFetch(g *gocui.Gui, v *gocui.View) error {
 		return err
 	}
 	go func() {
-		unamePassOpend, err := gui.fetch(g, v, true)
+		unamePassOpend := false
+		cancelled, err := gui.runTask(gui.Tr.SLocalize("FetchWait"), func(git *commands.GitCommand) error {
+			var err error
+			unamePassOpend, err = gui.fetch(g, v, git, true)
+			return err
+		})
+		if cancelled {
+			gui.closeCancelledLoader(unamePassOpend)
+			return
+		}
 		gui.HandleCredentialsPopup(g, unamePassOpend, err)
 	}()
 	return nil
@@ -195,7 +208,7 @@ func (gui *Gui) handleCheckoutBranch(branchName string) error {
 }
 
 func (gui *Gui) handleCheckoutByName(g *gocui.Gui, v *gocui.View) error {
-	gui.createPromptPanel(g, v, gui.Tr.SLocalize("BranchName")+":", func(g *gocui.Gui, v *gocui.View) error {
+	gui.createPromptPanel(g, v, gui.Tr.SLocalize("BranchName")+":", "", func(g *gocui.Gui, v *gocui.View) error {
 		return gui.handleCheckoutBranch(gui.trimmedContent(v))
 	})
 	return nil
@@ -209,7 +222,7 @@ func (gui *Gui) handleNewBranch(g *gocui.Gui, v *gocui.View) error {
 			"branchName": branch.Name,
 		},
 	)
-	gui.createPromptPanel(g, v, message, func(g *gocui.Gui, v *gocui.View) error {
+	gui.createPromptPanel(g, v, message, "", func(g *gocui.Gui, v *gocui.View) error {
 		if err := gui.GitCommand.NewBranch(gui.trimmedContent(v)); err != nil {
 			return gui.createErrorPanel(g, err.Error())
 		}
@@ -265,25 +278,65 @@ func (gui *Gui) deleteNamedBranch(g *gocui.Gui, v *gocui.View, selectedBranch *c
 	}, nil)
 }
 
+type mergeOption struct {
+	description string
+	flag        string
+	editMessage bool
+}
+
+// GetDisplayStrings is a function.
+func (o *mergeOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description, color.New(color.FgYellow).Sprint(strings.TrimSpace("git merge " + o.flag))}
+}
+
 func (gui *Gui) handleMerge(g *gocui.Gui, v *gocui.View) error {
 	checkedOutBranch := gui.State.Branches[0].Name
 	selectedBranch := gui.getSelectedBranch().Name
 	if checkedOutBranch == selectedBranch {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantMergeBranchIntoItself"))
 	}
-	prompt := gui.Tr.TemplateLocalize(
-		"ConfirmMerge",
+
+	options := []*mergeOption{
+		{description: gui.Tr.SLocalize("regularMerge"), flag: ""},
+		{description: gui.Tr.SLocalize("noFastForwardMerge"), flag: "--no-ff"},
+		{description: gui.Tr.SLocalize("fastForwardOnlyMerge"), flag: "--ff-only"},
+		{description: gui.Tr.SLocalize("squashMerge"), flag: "--squash"},
+		{description: gui.Tr.SLocalize("noFastForwardMergeWithMessage"), flag: "--no-ff", editMessage: true},
+	}
+
+	handleMenuPress := func(index int) error {
+		option := options[index]
+		if option.editMessage {
+			defaultMessage := fmt.Sprintf("Merge branch '%s' into %s", selectedBranch, checkedOutBranch)
+			return gui.createPromptPanel(g, v, gui.Tr.SLocalize("MergeCommitMessage"), defaultMessage, func(g *gocui.Gui, v *gocui.View) error {
+				return gui.mergeBranch(selectedBranch, option.flag, gui.trimmedContent(v))
+			})
+		}
+		return gui.mergeBranch(selectedBranch, option.flag, "")
+	}
+
+	title := gui.Tr.TemplateLocalize(
+		"MergeStrategyTitle",
 		Teml{
 			"checkedOutBranch": checkedOutBranch,
 			"selectedBranch":   selectedBranch,
 		},
 	)
-	return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("MergingTitle"), prompt,
-		func(g *gocui.Gui, v *gocui.View) error {
+	return gui.createMenu(title, options, len(options), handleMenuPress)
+}
 
-			err := gui.GitCommand.Merge(selectedBranch)
-			return gui.handleGenericMergeCommandResult(err)
-		}, nil)
+func (gui *Gui) mergeBranch(branchName string, flag string, message string) error {
+	err := gui.GitCommand.Merge(branchName, flag, message)
+	if err != nil || flag != "--squash" {
+		return gui.handleGenericMergeCommandResult(err)
+	}
+
+	// a squash merge leaves the changes staged without committing them, so we
+	// take the user straight to the commit message panel
+	if err := gui.refreshSidePanels(gui.g); err != nil {
+		return err
+	}
+	return gui.handleCommitPress(gui.g, gui.getFilesView())
 }
 
 func (gui *Gui) handleRebase(g *gocui.Gui, v *gocui.View) error {
@@ -321,17 +374,25 @@ func (gui *Gui) handleFastForward(g *gocui.Gui, v *gocui.View) error {
 	if branch.Pushables != "0" {
 		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("FwdCommitsToPush"))
 	}
-	upstream := "origin" // hardcoding for now
+	remote, remoteBranch, err := gui.GitCommand.GetUpstream(branch.Name)
+	if err != nil {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("FwdNoUpstream"))
+	}
 	message := gui.Tr.TemplateLocalize(
 		"Fetching",
 		Teml{
-			"from": fmt.Sprintf("%s/%s", upstream, branch.Name),
+			"from": fmt.Sprintf("%s/%s", remote, remoteBranch),
 			"to":   branch.Name,
 		},
 	)
 	go func() {
 		_ = gui.createLoaderPanel(gui.g, v, message)
-		if err := gui.GitCommand.FastForward(branch.Name); err != nil {
+		cancelled, err := gui.runTask(message, func(git *commands.GitCommand) error {
+			return git.FastForward(branch.Name, remote, remoteBranch)
+		})
+		if cancelled {
+			gui.closeCancelledLoader(false)
+		} else if err != nil {
 			_ = gui.createErrorPanel(gui.g, err.Error())
 		} else {
 			_ = gui.closeConfirmationPrompt(gui.g)
@@ -0,0 +1,205 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+)
+
+// the most commands we keep in the command log, dropping the oldest ones
+const maxCommandLogEntries = 500
+
+type commandLogItem struct {
+	entry commands.CmdLogEntry
+	// expanded tells us to show the command's output below it
+	expanded bool
+}
+
+type commandLogPanelState struct {
+	SelectedLine int
+	// EntryLines holds the line of the view that each entry starts on, as
+	// entries take up more than one line once expanded
+	EntryLines []int
+}
+
+// onCommandLogged adds a command that's been run to the command log. Commands
+// are run from all sorts of goroutines, so this is left to the gui's own
+// goroutine
+func (gui *Gui) onCommandLogged(entry commands.CmdLogEntry) {
+	gui.g.Update(func(*gocui.Gui) error {
+		panelState := gui.State.Panels.CommandLog
+		// we keep the newest command selected, unless an older one has been picked
+		following := panelState.SelectedLine == len(gui.State.CommandLog)-1
+
+		gui.State.CommandLog = append(gui.State.CommandLog, &commandLogItem{entry: entry})
+		if len(gui.State.CommandLog) > maxCommandLogEntries {
+			gui.State.CommandLog = gui.State.CommandLog[1:]
+			if panelState.SelectedLine > 0 {
+				panelState.SelectedLine--
+			}
+		}
+		if following {
+			panelState.SelectedLine = len(gui.State.CommandLog) - 1
+		}
+		return gui.renderCommandLog()
+	})
+}
+
+func (gui *Gui) renderCommandLog() error {
+	v, err := gui.g.View("commandLog")
+	if err != nil {
+		return nil
+	}
+	panelState := gui.State.Panels.CommandLog
+
+	lines := []string{}
+	panelState.EntryLines = make([]int, len(gui.State.CommandLog))
+	for i, item := range gui.State.CommandLog {
+		panelState.EntryLines[i] = len(lines)
+		lines = append(lines, gui.commandLogItemLines(item)...)
+	}
+
+	v.Clear()
+	fmt.Fprint(v, strings.Join(lines, "\n"))
+	if len(gui.State.CommandLog) == 0 {
+		return nil
+	}
+	return gui.focusPoint(0, panelState.EntryLines[panelState.SelectedLine], len(lines), v)
+}
+
+// commandLogItemLines shows when a command was run, how long it took, whether
+// it worked and the command itself, followed by its output if it's expanded
+func (gui *Gui) commandLogItemLines(item *commandLogItem) []string {
+	entry := item.entry
+	status := color.New(color.FgGreen).Sprintf("%-8s", "ok")
+	if entry.ExitCode == -1 {
+		status = color.New(color.FgRed).Sprintf("%-8s", "failed")
+	} else if entry.ExitCode != 0 {
+		status = color.New(color.FgRed).Sprintf("%-8s", fmt.Sprintf("exit %d", entry.ExitCode))
+	}
+	lines := []string{fmt.Sprintf(
+		"%s %8s %s%s",
+		entry.Start.Format("15:04:05"),
+		entry.Duration.Round(time.Millisecond),
+		status,
+		color.New(color.FgCyan).Sprint(entry.Command),
+	)}
+	if !item.expanded {
+		return lines
+	}
+
+	output := strings.TrimRight(strings.Replace(entry.Output, "\r\n", "\n", -1), "\n")
+	if output == "" {
+		return append(lines, "    "+gui.Tr.SLocalize("CommandLogNoOutput"))
+	}
+	for _, line := range strings.Split(output, "\n") {
+		// progress meters write over themselves with carriage returns, so we
+		// only keep what they wrote last
+		line = strings.TrimRight(line, "\r")
+		if i := strings.LastIndex(line, "\r"); i != -1 {
+			line = line[i+1:]
+		}
+		lines = append(lines, "    "+line)
+	}
+	return lines
+}
+
+func (gui *Gui) handleCommandLogSelect(g *gocui.Gui, v *gocui.View) error {
+	return gui.renderCommandLog()
+}
+
+func (gui *Gui) handleCommandLogNextLine(g *gocui.Gui, v *gocui.View) error {
+	panelState := gui.State.Panels.CommandLog
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, len(gui.State.CommandLog), false)
+
+	return gui.handleCommandLogSelect(g, v)
+}
+
+func (gui *Gui) handleCommandLogPrevLine(g *gocui.Gui, v *gocui.View) error {
+	panelState := gui.State.Panels.CommandLog
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, len(gui.State.CommandLog), true)
+
+	return gui.handleCommandLogSelect(g, v)
+}
+
+// handleCommandLogToggleExpand shows or hides the output of the selected command
+func (gui *Gui) handleCommandLogToggleExpand(g *gocui.Gui, v *gocui.View) error {
+	if len(gui.State.CommandLog) == 0 {
+		return nil
+	}
+	item := gui.State.CommandLog[gui.State.Panels.CommandLog.SelectedLine]
+	item.expanded = !item.expanded
+	return gui.renderCommandLog()
+}
+
+// handleCommandLogClick selects the command that was clicked on, or shows or
+// hides its output if it was already selected
+func (gui *Gui) handleCommandLogClick(g *gocui.Gui, v *gocui.View) error {
+	if gui.popupPanelFocused() {
+		return nil
+	}
+
+	panelState := gui.State.Panels.CommandLog
+	_, cy := v.Cursor()
+	_, oy := v.Origin()
+	clicked := -1
+	for i, line := range panelState.EntryLines {
+		if line <= cy+oy {
+			clicked = i
+		}
+	}
+
+	if gui.currentViewName() != v.Name() {
+		if clicked != -1 {
+			panelState.SelectedLine = clicked
+		}
+		return gui.switchFocus(g, g.CurrentView(), v)
+	}
+	if clicked == -1 {
+		return nil
+	}
+	if clicked == panelState.SelectedLine {
+		return gui.handleCommandLogToggleExpand(g, v)
+	}
+	panelState.SelectedLine = clicked
+	return gui.handleCommandLogSelect(g, v)
+}
+
+// handleToggleCommandLog shows or hides the command log below the main panel
+func (gui *Gui) handleToggleCommandLog(g *gocui.Gui, v *gocui.View) error {
+	gui.State.ShowCommandLog = !gui.State.ShowCommandLog
+	if !gui.State.ShowCommandLog && gui.currentViewName() == "commandLog" {
+		return gui.handleCommandLogEscape(g, v)
+	}
+	return nil
+}
+
+func (gui *Gui) handleFocusCommandLog(g *gocui.Gui, v *gocui.View) error {
+	if gui.popupPanelFocused() || gui.currentViewName() == "commandLog" {
+		return nil
+	}
+	commandLogView, err := g.View("commandLog")
+	if err != nil {
+		return nil
+	}
+	gui.State.ShowCommandLog = true
+	return gui.switchFocus(g, v, commandLogView)
+}
+
+// handleCommandLogEscape goes back to the side panel we came from
+func (gui *Gui) handleCommandLogEscape(g *gocui.Gui, v *gocui.View) error {
+	for _, viewName := range cyclableViews {
+		if viewName == gui.State.PreviousView {
+			previousView, err := g.View(viewName)
+			if err != nil {
+				return err
+			}
+			return gui.switchFocus(g, nil, previousView)
+		}
+	}
+	return gui.switchFocus(g, nil, gui.getFilesView())
+}
@@ -23,23 +23,26 @@ func (gui *Gui) handleCommitFileSelect(g *gocui.Gui, v *gocui.View) error {
 	if err := gui.focusPoint(0, gui.State.Panels.CommitFiles.SelectedLine, len(gui.State.CommitFiles), v); err != nil {
 		return err
 	}
-	commitText, err := gui.GitCommand.ShowCommitFile(commitFile.Sha, commitFile.Name)
-	if err != nil {
-		return err
-	}
-	return gui.renderString(g, "main", commitText)
+	gui.showDiff(
+		func() []string {
+			return []string{gui.GitCommand.ShowCommitFileCommand(commitFile.Sha, commitFile.Name)}
+		},
+		func() (string, error) { return gui.GitCommand.ShowCommitFile(commitFile.Sha, commitFile.Name) },
+		false,
+	)
+	return nil
 }
 
 func (gui *Gui) handleCommitFilesNextLine(g *gocui.Gui, v *gocui.View) error {
 	panelState := gui.State.Panels.CommitFiles
-	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.CommitFiles), false)
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, len(gui.State.CommitFiles), false)
 
 	return gui.handleCommitFileSelect(gui.g, v)
 }
 
 func (gui *Gui) handleCommitFilesPrevLine(g *gocui.Gui, v *gocui.View) error {
 	panelState := gui.State.Panels.CommitFiles
-	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.CommitFiles), true)
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, len(gui.State.CommitFiles), true)
 
 	return gui.handleCommitFileSelect(gui.g, v)
 }
@@ -66,8 +69,8 @@ func (gui *Gui) handleDiscardOldFileChange(g *gocui.Gui, v *gocui.View) error {
 	fileName := gui.State.CommitFiles[gui.State.Panels.CommitFiles.SelectedLine].Name
 
 	return gui.createConfirmationPanel(gui.g, v, gui.Tr.SLocalize("DiscardFileChangesTitle"), gui.Tr.SLocalize("DiscardFileChangesPrompt"), func(g *gocui.Gui, v *gocui.View) error {
-		return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
-			if err := gui.GitCommand.DiscardOldFileChanges(gui.State.Commits, gui.State.Panels.Commits.SelectedLine, fileName); err != nil {
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func(git *commands.GitCommand) error {
+			if err := git.DiscardOldFileChanges(gui.State.Commits, gui.State.Panels.Commits.SelectedLine, fileName); err != nil {
 				if err := gui.handleGenericMergeCommandResult(err); err != nil {
 					return err
 				}
@@ -1,11 +1,17 @@
 package gui
 
 import (
+	"fmt"
+	"io"
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/theme"
+	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
 // runSyncOrAsyncCommand takes the output of a command that may have returned
@@ -25,17 +31,118 @@ func (gui *Gui) runSyncOrAsyncCommand(sub *exec.Cmd, err error) (bool, error) {
 	return true, nil
 }
 
+// getCommitMessage joins the subject and description panels into a single
+// commit message, separating the two with a blank line as git expects
+func (gui *Gui) getCommitMessage() string {
+	subject := gui.trimmedContent(gui.getCommitMessageView())
+	description := gui.trimmedContent(gui.getCommitDescriptionView())
+	if description == "" {
+		return subject
+	}
+	return subject + "\n\n" + description
+}
+
+// setCommitMessage splits a commit message into its subject line and its
+// description and renders them into their respective panels
+func (gui *Gui) setCommitMessage(message string) {
+	lines := strings.SplitN(message, "\n", 2)
+	subject := lines[0]
+	description := ""
+	if len(lines) > 1 {
+		description = strings.TrimSpace(lines[1])
+	}
+
+	subjectView := gui.getCommitMessageView()
+	subjectView.Clear()
+	fmt.Fprint(subjectView, subject)
+	_ = subjectView.SetOrigin(0, 0)
+	_ = subjectView.SetCursor(len([]rune(subject)), 0)
+
+	descriptionView := gui.getCommitDescriptionView()
+	descriptionView.Clear()
+	fmt.Fprint(descriptionView, description)
+	_ = descriptionView.SetOrigin(0, 0)
+	_ = descriptionView.SetCursor(0, 0)
+}
+
+// getCommitMessagePrefill returns the message the commit panels should start
+// with when they're empty: the message git prepared for the merge we're in the
+// middle of, or otherwise the user's commit template, prefixed with whatever
+// the git.commitPrefix config derives from the branch name
+func (gui *Gui) getCommitMessagePrefill() string {
+	if gui.State.WorkingTreeState == "merging" {
+		if message := gui.GitCommand.GetMergeMessage(); message != "" {
+			return message
+		}
+	}
+	prefix := ""
+	if len(gui.State.Branches) > 0 {
+		prefix = gui.GitCommand.GetCommitPrefix(gui.State.Branches[0].Name)
+	}
+	return prefix + gui.GitCommand.GetCommitTemplate()
+}
+
 func (gui *Gui) handleCommitConfirm(g *gocui.Gui, v *gocui.View) error {
-	message := gui.trimmedContent(v)
-	if message == "" {
+	message := gui.getCommitMessage()
+	if gui.trimmedContent(gui.getCommitMessageView()) == "" {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("CommitWithoutMessageErr"))
 	}
-	flags := ""
-	skipHookPrefix := gui.Config.GetUserConfig().GetString("git.skipHookPrefix")
-	if skipHookPrefix != "" && strings.HasPrefix(message, skipHookPrefix) {
-		flags = "--no-verify"
+	// we save the draft before committing so that it survives a rejected commit
+	if !gui.State.Panels.CommitMessage.Amend {
+		if err := gui.saveCommitMessageDraft(); err != nil {
+			return err
+		}
+	}
+	return gui.commit(v, message, gui.State.Panels.CommitMessage.SkipHooks)
+}
+
+// commit commits what's staged with the given message. If the repo has hooks
+// that would run, they're run in the background with their output shown in the
+// hook output panel, from where the commit can be retried without the hooks
+func (gui *Gui) commit(v *gocui.View, message string, noVerify bool) error {
+	flags := []string{}
+	if gui.State.Panels.CommitMessage.Amend {
+		flags = append(flags, "--amend")
+	}
+	if noVerify {
+		flags = append(flags, "--no-verify")
+	}
+
+	if noVerify || !(gui.GitCommand.HasHook("pre-commit") || gui.GitCommand.HasHook("commit-msg")) {
+		sub, err := gui.GitCommand.Commit(message, strings.Join(flags, " "), nil)
+		return gui.onCommitDone(v, message, sub, err)
+	}
+
+	var sub *exec.Cmd
+	run := func(git *commands.GitCommand, stream io.Writer) error {
+		var err error
+		sub, err = git.Commit(message, strings.Join(flags, " "), stream)
+		return err
+	}
+	onSuccess := func() error {
+		return gui.onCommitDone(v, message, sub, nil)
 	}
-	ok, err := gui.runSyncOrAsyncCommand(gui.GitCommand.Commit(message, flags))
+	retryNoVerify := func() error {
+		return gui.commit(v, message, true)
+	}
+	// the hook output panel returns focus to the previous view when it's
+	// closed, which should be the commit panel if the commit fails
+	gui.State.PreviousView = v.Name()
+	return gui.runWithHookOutput(v, gui.Tr.SLocalize("CommittingStatus"), run, onSuccess, retryNoVerify)
+}
+
+func (gui *Gui) onCommitDone(v *gocui.View, message string, sub *exec.Cmd, err error) error {
+	if err == nil {
+		// either we've committed, or we're about to hand over to a subprocess
+		// which we won't hear back from, so the draft has served its purpose
+		repoState := gui.getRepoState()
+		repoState.AddToCommitMessageHistory(message)
+		repoState.CommitMessageDraft = ""
+		if err := gui.Config.SaveAppState(); err != nil {
+			return err
+		}
+	}
+	ok, err := gui.runSyncOrAsyncCommand(sub, err)
 	if err != nil {
 		return err
 	}
@@ -43,43 +150,322 @@ func (gui *Gui) handleCommitConfirm(g *gocui.Gui, v *gocui.View) error {
 		return nil
 	}
 
-	v.Clear()
-	_ = v.SetCursor(0, 0)
-	_ = v.SetOrigin(0, 0)
-	_, _ = g.SetViewOnBottom("commitMessage")
-	_ = gui.switchFocus(g, v, gui.getFilesView())
-	return gui.refreshSidePanels(g)
+	gui.setCommitMessage("")
+	if err := gui.closeCommitMessagePanel(gui.g, v); err != nil {
+		return err
+	}
+	return gui.refreshSidePanels(gui.g)
+}
+
+// handleToggleSkipHooks switches between committing with and without running
+// the pre-commit and commit-msg hooks
+func (gui *Gui) handleToggleSkipHooks(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.CommitMessage
+	state.SkipHooks = !state.SkipHooks
+	gui.RenderCommitLength()
+	return nil
 }
 
 func (gui *Gui) handleCommitClose(g *gocui.Gui, v *gocui.View) error {
-	g.SetViewOnBottom("commitMessage")
+	if gui.State.Panels.CommitMessage.Amend {
+		// the amended commit's message is not a draft worth keeping around
+		gui.setCommitMessage("")
+	} else if err := gui.saveCommitMessageDraft(); err != nil {
+		return err
+	}
+	return gui.closeCommitMessagePanel(g, v)
+}
+
+// saveCommitMessageDraft persists what has been typed into the commit panels,
+// so that we can restore it the next time the panels are opened, even if
+// that's after a restart
+func (gui *Gui) saveCommitMessageDraft() error {
+	gui.getRepoState().CommitMessageDraft = gui.getCommitMessage()
+	return gui.Config.SaveAppState()
+}
+
+func (gui *Gui) handleCommitMessageHistoryPrev(g *gocui.Gui, v *gocui.View) error {
+	return gui.cycleCommitMessageHistory(1)
+}
+
+func (gui *Gui) handleCommitMessageHistoryNext(g *gocui.Gui, v *gocui.View) error {
+	return gui.cycleCommitMessageHistory(-1)
+}
+
+// cycleCommitMessageHistory moves through the messages previously committed in
+// this repo, most recent first. Moving past the most recent message brings
+// back whatever the user had typed before they started cycling
+func (gui *Gui) cycleCommitMessageHistory(change int) error {
+	history := gui.getRepoState().CommitMessageHistory
+	state := gui.State.Panels.CommitMessage
+	newIndex := state.HistoryIndex + change
+	if newIndex < -1 || newIndex >= len(history) {
+		return nil
+	}
+
+	if state.HistoryIndex == -1 {
+		state.Draft = gui.getCommitMessage()
+	}
+	state.HistoryIndex = newIndex
+	if newIndex == -1 {
+		gui.setCommitMessage(state.Draft)
+	} else {
+		gui.setCommitMessage(history[newIndex])
+	}
+	gui.RenderCommitLength()
+	return nil
+}
+
+type trailerOption struct {
+	key string
+}
+
+// GetDisplayStrings is a function.
+func (o *trailerOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.key}
+}
+
+type trailerValueOption struct {
+	value string
+}
+
+// GetDisplayStrings is a function.
+func (o *trailerValueOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.value}
+}
+
+// handleCreateTrailersMenu lets the user add a trailer like Co-authored-by to
+// the commit message. Besides the standard trailers, any listed under
+// git.trailers.keys in the config are offered
+func (gui *Gui) handleCreateTrailersMenu(g *gocui.Gui, v *gocui.View) error {
+	keys := []string{"Co-authored-by", "Signed-off-by", "Reviewed-by"}
+	for _, key := range gui.Config.GetUserConfig().GetStringSlice("git.trailers.keys") {
+		if !utils.IncludesString(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	options := make([]*trailerOption, len(keys))
+	for i, key := range keys {
+		options[i] = &trailerOption{key: key}
+	}
+
+	// the menu returns focus to the previous view once it's done, and as the
+	// commit panels are popups they aren't recorded as such unless we do it
+	// ourselves. closeCommitMessagePanel sets things right again
+	gui.State.PreviousView = v.Name()
+
+	handleMenuPress := func(index int) error {
+		key := options[index].key
+		if key == "Signed-off-by" {
+			identity, err := gui.GitCommand.GetCurrentUserIdentity()
+			if err != nil {
+				return gui.createErrorPanel(g, err.Error())
+			}
+			return gui.addTrailer(key, identity)
+		}
+		return gui.createTrailerValueMenu(key)
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("AddTrailerTitle"), options, len(options), handleMenuPress)
+}
+
+// createTrailerValueMenu lists the people configured under git.trailers.people
+// followed by everyone who has authored a commit in the repo
+func (gui *Gui) createTrailerValueMenu(key string) error {
+	people := gui.Config.GetUserConfig().GetStringSlice("git.trailers.people")
+	authors, err := gui.GitCommand.GetAuthors()
+	if err != nil {
+		gui.Log.Error(err)
+	}
+	for _, author := range authors {
+		if !utils.IncludesString(people, author) {
+			people = append(people, author)
+		}
+	}
+	if len(people) == 0 {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NoTrailerValues"))
+	}
+
+	options := make([]*trailerValueOption, len(people))
+	for i, person := range people {
+		options[i] = &trailerValueOption{value: person}
+	}
+
+	handleMenuPress := func(index int) error {
+		return gui.addTrailer(key, options[index].value)
+	}
+
+	return gui.createMenu(key, options, len(options), handleMenuPress)
+}
+
+func (gui *Gui) addTrailer(key string, value string) error {
+	message, err := gui.GitCommand.AddTrailer(gui.getCommitMessage(), key, value)
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+	gui.setCommitMessage(message)
+	gui.RenderCommitLength()
+	return nil
+}
+
+func (gui *Gui) closeCommitMessagePanel(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Panels.CommitMessage.Amend = false
+	gui.State.Panels.CommitMessage.HistoryIndex = -1
+	gui.State.Panels.CommitMessage.SkipHooks = false
+	// the commit panels are only ever opened from the files panel
+	gui.State.PreviousView = "files"
+	_, _ = g.SetViewOnBottom("commitDescription")
+	_, _ = g.SetViewOnBottom("commitMessage")
 	return gui.switchFocus(g, v, gui.getFilesView())
 }
 
+// handleCommitDescriptionNewLine is needed because enter is bound in the
+// description panel, so it never reaches the view's editor
+func (gui *Gui) handleCommitDescriptionNewLine(g *gocui.Gui, v *gocui.View) error {
+	v.EditNewLine()
+	return nil
+}
+
+func (gui *Gui) handleSwitchToCommitDescription(g *gocui.Gui, v *gocui.View) error {
+	return gui.switchFocus(g, v, gui.getCommitDescriptionView())
+}
+
+func (gui *Gui) handleSwitchToCommitMessage(g *gocui.Gui, v *gocui.View) error {
+	return gui.switchFocus(g, v, gui.getCommitMessageView())
+}
+
 func (gui *Gui) handleCommitFocused(g *gocui.Gui, v *gocui.View) error {
+	if _, err := g.SetViewOnTop("commitDescription"); err != nil {
+		return err
+	}
+	if _, err := g.SetViewOnTop("commitMessage"); err != nil {
+		return err
+	}
+
+	message := gui.Tr.TemplateLocalize(
+		"CommitMessageOptions",
+		Teml{
+			"keyBindClose":   gui.getKeyDisplay("universal.return"),
+			"keyBindConfirm": gui.getKeyDisplay("universal.confirm"),
+			"keyBindSwitch":  gui.getKeyDisplay("universal.togglePanel"),
+			"keyBindHistory": fmt.Sprintf("%s/%s", gui.getKeyDisplay("universal.prevItem"), gui.getKeyDisplay("universal.nextItem")),
+			"keyBindTrailer": gui.getKeyDisplay("commitMessage.addTrailer"),
+			"keyBindHooks":   gui.getKeyDisplay("commitMessage.toggleSkipHooks"),
+		},
+	)
+	return gui.renderString(g, "options", message)
+}
+
+func (gui *Gui) handleCommitDescriptionFocused(g *gocui.Gui, v *gocui.View) error {
 	if _, err := g.SetViewOnTop("commitMessage"); err != nil {
 		return err
 	}
+	if _, err := g.SetViewOnTop("commitDescription"); err != nil {
+		return err
+	}
 
 	message := gui.Tr.TemplateLocalize(
-		"CloseConfirm",
+		"CommitDescriptionOptions",
 		Teml{
-			"keyBindClose":   "esc",
-			"keyBindConfirm": "enter",
+			"keyBindClose":   gui.getKeyDisplay("universal.return"),
+			"keyBindConfirm": gui.getKeyDisplay("commitMessage.confirm"),
+			"keyBindNewLine": gui.getKeyDisplay("commitMessage.newLine"),
+			"keyBindSwitch":  gui.getKeyDisplay("universal.togglePanel"),
+			"keyBindTrailer": gui.getKeyDisplay("commitMessage.addTrailer"),
+			"keyBindHooks":   gui.getKeyDisplay("commitMessage.toggleSkipHooks"),
 		},
 	)
 	return gui.renderString(g, "options", message)
 }
 
+// resizeCommitMessagePanels lays out the single-line subject panel with the
+// description panel directly beneath it, growing the description panel with
+// its content
+func (gui *Gui) resizeCommitMessagePanels(g *gocui.Gui) error {
+	width, height := g.Size()
+	panelWidth := width / 2
+	descriptionHeight := len(gui.getCommitDescriptionView().BufferLines()) + 1
+	if descriptionHeight < 5 {
+		descriptionHeight = 5
+	}
+	if descriptionHeight > height/2 {
+		descriptionHeight = height / 2
+	}
+
+	// the subject panel takes up three rows including its frame
+	totalHeight := 3 + descriptionHeight + 2
+	x0 := width/2 - panelWidth/2
+	x1 := width/2 + panelWidth/2
+	y0 := height/2 - totalHeight/2
+
+	if _, err := g.SetView("commitMessage", x0, y0, x1, y0+2, 0); err != nil {
+		return err
+	}
+	_, err := g.SetView("commitDescription", x0, y0+3, x1, y0+3+descriptionHeight+1, 0)
+	return err
+}
+
 func (gui *Gui) getBufferLength(view *gocui.View) string {
 	return " " + strconv.Itoa(strings.Count(view.Buffer(), "")-1) + " "
 }
 
-// RenderCommitLength is a function.
+// RenderCommitLength renders the length of the commit summary, along with any
+// of the configured commit message rules the message currently breaks
 func (gui *Gui) RenderCommitLength() {
+	v := gui.getCommitMessageView()
+	title := gui.Tr.SLocalize("CommitMessage")
+	if gui.State.Panels.CommitMessage.SkipHooks {
+		title = fmt.Sprintf("%s [%s]", title, gui.Tr.SLocalize("SkippingHooks"))
+	}
+	violations := gui.GitCommand.CommitMessageViolations(gui.getCommitMessage())
+	if len(violations) == 0 {
+		v.Title = title
+		v.FgColor = theme.GocuiDefaultTextColor
+	} else {
+		v.Title = fmt.Sprintf("%s (%s)", title, strings.Join(violations, ", "))
+		v.FgColor = gocui.ColorRed
+	}
+
 	if !gui.Config.GetUserConfig().GetBool("gui.commitLength.show") {
 		return
 	}
-	v := gui.getCommitMessageView()
 	v.Subtitle = gui.getBufferLength(v)
 }
+
+// commitMessageEditor re-renders the commit length and rule violations as the
+// user types into either of the commit panels, and saves what they've typed as
+// the draft once they stop for a moment
+func (gui *Gui) commitMessageEditor() gocui.Editor {
+	return gocui.EditorFunc(func(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+		gocui.DefaultEditor.Edit(v, key, ch, mod)
+		gui.RenderCommitLength()
+		gui.scheduleCommitMessageDraftSave()
+	})
+}
+
+// how long we wait after the last keypress before saving the commit message
+// draft, so that we're not writing the state file on every keypress
+const commitMessageDraftSaveDelay = 500 * time.Millisecond
+
+// scheduleCommitMessageDraftSave saves the commit message draft once the user
+// has stopped typing for a moment, so that it survives lazygit crashing
+func (gui *Gui) scheduleCommitMessageDraftSave() {
+	state := gui.State.Panels.CommitMessage
+	if state.Amend {
+		return
+	}
+	if state.draftSaveTimer != nil {
+		state.draftSaveTimer.Stop()
+	}
+	state.draftSaveTimer = time.AfterFunc(commitMessageDraftSaveDelay, func() {
+		gui.g.Update(func(g *gocui.Gui) error {
+			if gui.State.Panels.CommitMessage.Amend {
+				return nil
+			}
+			if err := gui.saveCommitMessageDraft(); err != nil {
+				gui.Log.Error(err)
+			}
+			return nil
+		})
+	})
+}
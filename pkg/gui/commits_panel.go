@@ -46,45 +46,63 @@ func (gui *Gui) handleCommitSelect(g *gocui.Gui, v *gocui.View) error {
 		return nil
 	}
 
-	commitText, err := gui.GitCommand.Show(commit.Sha)
-	if err != nil {
-		return err
-	}
-	return gui.renderString(g, "main", commitText)
+	gui.showDiff(
+		func() []string { return gui.GitCommand.ShowCommands(commit.Sha) },
+		func() (string, error) { return gui.GitCommand.Show(commit.Sha) },
+		false,
+	)
+	return nil
 }
 
 func (gui *Gui) refreshCommits(g *gocui.Gui) error {
-	g.Update(func(*gocui.Gui) error {
-		builder, err := git.NewCommitListBuilder(gui.Log, gui.GitCommand, gui.OSCommand, gui.Tr, gui.State.CherryPickedCommits, gui.State.DiffEntries)
-		if err != nil {
-			return err
+	cherryPickedCommits, diffEntries := gui.State.CherryPickedCommits, gui.State.DiffEntries
+	// the commits we've picked out are shown in the list, so the list changes
+	// with them as well as with the refs
+	key := func() string {
+		fingerprint := gui.GitCommand.RefsFingerprint()
+		if fingerprint == "" {
+			return ""
 		}
-		commits, err := builder.GetCommits()
-		if err != nil {
-			return err
+		for _, commit := range cherryPickedCommits {
+			fingerprint += "cherry-picked " + commit.Sha + "\n"
 		}
-		gui.State.Commits = commits
-
-		gui.refreshSelectedLine(&gui.State.Panels.Commits.SelectedLine, len(gui.State.Commits))
-
-		isFocused := gui.g.CurrentView().Name() == "commits"
-		list, err := utils.RenderList(gui.State.Commits, isFocused)
-		if err != nil {
-			return err
+		for _, commit := range diffEntries {
+			fingerprint += "diffing " + commit.Sha + "\n"
 		}
-
-		v := gui.getCommitsView()
-		v.Clear()
-		fmt.Fprint(v, list)
-
-		gui.refreshStatus(g)
-		if g.CurrentView() == v {
-			gui.handleCommitSelect(g, v)
+		return fingerprint
+	}
+	gui.loadCachedInBackground("commits", key, func() (func(*gocui.Gui) error, error) {
+		builder, err := git.NewCommitListBuilder(gui.Log, gui.GitCommand, gui.OSCommand, gui.Tr, cherryPickedCommits, diffEntries)
+		if err != nil {
+			return nil, err
 		}
-		if g.CurrentView() == gui.getCommitFilesView() {
-			return gui.refreshCommitFilesView()
+		commits, err := builder.GetCommits()
+		if err != nil {
+			return nil, err
 		}
-		return nil
+		return func(g *gocui.Gui) error {
+			gui.State.Commits = commits
+
+			gui.refreshSelectedLine(&gui.State.Panels.Commits.SelectedLine, len(gui.State.Commits))
+
+			isFocused := gui.g.CurrentView().Name() == "commits"
+			list, err := utils.RenderList(gui.State.Commits, isFocused)
+			if err != nil {
+				return err
+			}
+
+			v := gui.getCommitsView()
+			gui.renderListContent(v, list)
+
+			gui.refreshStatus(g)
+			if g.CurrentView() == v {
+				gui.handleCommitSelect(g, v)
+			}
+			if g.CurrentView() == gui.getCommitFilesView() {
+				return gui.refreshCommitFilesView()
+			}
+			return nil
+		}, nil
 	})
 	return nil
 }
@@ -95,7 +113,7 @@ func (gui *Gui) handleCommitsNextLine(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	panelState := gui.State.Panels.Commits
-	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.Commits), false)
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, len(gui.State.Commits), false)
 
 	if err := gui.resetOrigin(gui.getMainView()); err != nil {
 		return err
@@ -109,7 +127,7 @@ func (gui *Gui) handleCommitsPrevLine(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	panelState := gui.State.Panels.Commits
-	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.Commits), true)
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, len(gui.State.Commits), true)
 
 	if err := gui.resetOrigin(gui.getMainView()); err != nil {
 		return err
@@ -155,8 +173,8 @@ func (gui *Gui) handleCommitSquashDown(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("Squash"), gui.Tr.SLocalize("SureSquashThisCommit"), func(g *gocui.Gui, v *gocui.View) error {
-		return gui.WithWaitingStatus(gui.Tr.SLocalize("SquashingStatus"), func() error {
-			err := gui.GitCommand.InteractiveRebase(gui.State.Commits, gui.State.Panels.Commits.SelectedLine, "squash")
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("SquashingStatus"), func(git *commands.GitCommand) error {
+			err := git.InteractiveRebase(gui.State.Commits, gui.State.Panels.Commits.SelectedLine, "squash")
 			return gui.handleGenericMergeCommandResult(err)
 		})
 	}, nil)
@@ -187,8 +205,8 @@ func (gui *Gui) handleCommitFixup(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("Fixup"), gui.Tr.SLocalize("SureFixupThisCommit"), func(g *gocui.Gui, v *gocui.View) error {
-		return gui.WithWaitingStatus(gui.Tr.SLocalize("FixingStatus"), func() error {
-			err := gui.GitCommand.InteractiveRebase(gui.State.Commits, gui.State.Panels.Commits.SelectedLine, "fixup")
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("FixingStatus"), func(git *commands.GitCommand) error {
+			err := git.InteractiveRebase(gui.State.Commits, gui.State.Panels.Commits.SelectedLine, "fixup")
 			return gui.handleGenericMergeCommandResult(err)
 		})
 	}, nil)
@@ -207,7 +225,7 @@ func (gui *Gui) handleRenameCommit(g *gocui.Gui, v *gocui.View) error {
 	if gui.State.Panels.Commits.SelectedLine != 0 {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("OnlyRenameTopCommit"))
 	}
-	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("renameCommit"), func(g *gocui.Gui, v *gocui.View) error {
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("renameCommit"), "", func(g *gocui.Gui, v *gocui.View) error {
 		if err := gui.GitCommand.RenameCommit(v.Buffer()); err != nil {
 			return gui.createErrorPanel(g, err.Error())
 		}
@@ -287,8 +305,8 @@ func (gui *Gui) handleCommitDelete(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	return gui.createConfirmationPanel(gui.g, v, gui.Tr.SLocalize("DeleteCommitTitle"), gui.Tr.SLocalize("DeleteCommitPrompt"), func(*gocui.Gui, *gocui.View) error {
-		return gui.WithWaitingStatus(gui.Tr.SLocalize("DeletingStatus"), func() error {
-			err := gui.GitCommand.InteractiveRebase(gui.State.Commits, gui.State.Panels.Commits.SelectedLine, "drop")
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("DeletingStatus"), func(git *commands.GitCommand) error {
+			err := git.InteractiveRebase(gui.State.Commits, gui.State.Panels.Commits.SelectedLine, "drop")
 			return gui.handleGenericMergeCommandResult(err)
 		})
 	}, nil)
@@ -308,8 +326,8 @@ func (gui *Gui) handleCommitMoveDown(g *gocui.Gui, v *gocui.View) error {
 		return gui.refreshCommits(gui.g)
 	}
 
-	return gui.WithWaitingStatus(gui.Tr.SLocalize("MovingStatus"), func() error {
-		err := gui.GitCommand.MoveCommitDown(gui.State.Commits, index)
+	return gui.WithWaitingStatus(gui.Tr.SLocalize("MovingStatus"), func(git *commands.GitCommand) error {
+		err := git.MoveCommitDown(gui.State.Commits, index)
 		if err == nil {
 			gui.State.Panels.Commits.SelectedLine++
 		}
@@ -331,8 +349,8 @@ func (gui *Gui) handleCommitMoveUp(g *gocui.Gui, v *gocui.View) error {
 		return gui.refreshCommits(gui.g)
 	}
 
-	return gui.WithWaitingStatus(gui.Tr.SLocalize("MovingStatus"), func() error {
-		err := gui.GitCommand.MoveCommitDown(gui.State.Commits, index-1)
+	return gui.WithWaitingStatus(gui.Tr.SLocalize("MovingStatus"), func(git *commands.GitCommand) error {
+		err := git.MoveCommitDown(gui.State.Commits, index-1)
 		if err == nil {
 			gui.State.Panels.Commits.SelectedLine--
 		}
@@ -349,16 +367,16 @@ func (gui *Gui) handleCommitEdit(g *gocui.Gui, v *gocui.View) error {
 		return nil
 	}
 
-	return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
-		err = gui.GitCommand.InteractiveRebase(gui.State.Commits, gui.State.Panels.Commits.SelectedLine, "edit")
+	return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func(git *commands.GitCommand) error {
+		err = git.InteractiveRebase(gui.State.Commits, gui.State.Panels.Commits.SelectedLine, "edit")
 		return gui.handleGenericMergeCommandResult(err)
 	})
 }
 
 func (gui *Gui) handleCommitAmendTo(g *gocui.Gui, v *gocui.View) error {
 	return gui.createConfirmationPanel(gui.g, v, gui.Tr.SLocalize("AmendCommitTitle"), gui.Tr.SLocalize("AmendCommitPrompt"), func(*gocui.Gui, *gocui.View) error {
-		return gui.WithWaitingStatus(gui.Tr.SLocalize("AmendingStatus"), func() error {
-			err := gui.GitCommand.AmendTo(gui.State.Commits[gui.State.Panels.Commits.SelectedLine].Sha)
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("AmendingStatus"), func(git *commands.GitCommand) error {
+			err := git.AmendTo(gui.State.Commits[gui.State.Panels.Commits.SelectedLine].Sha)
 			return gui.handleGenericMergeCommandResult(err)
 		})
 	}, nil)
@@ -442,8 +460,8 @@ func (gui *Gui) handleCopyCommitRange(g *gocui.Gui, v *gocui.View) error {
 // HandlePasteCommits begins a cherry-pick rebase with the commits the user has copied
 func (gui *Gui) HandlePasteCommits(g *gocui.Gui, v *gocui.View) error {
 	return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("CherryPick"), gui.Tr.SLocalize("SureCherryPick"), func(g *gocui.Gui, v *gocui.View) error {
-		return gui.WithWaitingStatus(gui.Tr.SLocalize("CherryPickingStatus"), func() error {
-			err := gui.GitCommand.CherryPickCommits(gui.State.CherryPickedCommits)
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("CherryPickingStatus"), func(git *commands.GitCommand) error {
+			err := git.CherryPickCommits(gui.State.CherryPickedCommits)
 			return gui.handleGenericMergeCommandResult(err)
 		})
 	}, nil)
@@ -480,13 +498,12 @@ func (gui *Gui) handleToggleDiffCommit(g *gocui.Gui, v *gocui.View) error {
 
 	// if selected two commits, display diff between
 	if len(gui.State.DiffEntries) == selectLimit {
-		commitText, err := gui.GitCommand.DiffCommits(gui.State.DiffEntries[0].Sha, gui.State.DiffEntries[1].Sha)
-
-		if err != nil {
-			return gui.createErrorPanel(gui.g, err.Error())
-		}
-
-		return gui.renderString(g, "main", commitText)
+		sha1, sha2 := gui.State.DiffEntries[0].Sha, gui.State.DiffEntries[1].Sha
+		gui.showDiff(
+			func() []string { return []string{gui.GitCommand.DiffCommitsCommand(sha1, sha2)} },
+			func() (string, error) { return gui.GitCommand.DiffCommits(sha1, sha2) },
+			false,
+		)
 	}
 
 	return nil
@@ -550,8 +567,8 @@ func (gui *Gui) handleSquashAllAboveFixupCommits(g *gocui.Gui, v *gocui.View) er
 			"commit": commit.Sha,
 		},
 	), func(g *gocui.Gui, v *gocui.View) error {
-		return gui.WithWaitingStatus(gui.Tr.SLocalize("SquashingStatus"), func() error {
-			err := gui.GitCommand.SquashAllAboveFixupCommits(commit.Sha)
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("SquashingStatus"), func(git *commands.GitCommand) error {
+			err := git.SquashAllAboveFixupCommits(commit.Sha)
 			return gui.handleGenericMergeCommandResult(err)
 		})
 	}, nil)
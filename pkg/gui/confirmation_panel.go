@@ -7,6 +7,7 @@
 package gui
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -62,16 +63,94 @@ func (gui *Gui) getConfirmationPanelDimensions(g *gocui.Gui, wrap bool, prompt s
 		height/2 + panelHeight/2
 }
 
-func (gui *Gui) createPromptPanel(g *gocui.Gui, currentView *gocui.View, title string, handleConfirm func(*gocui.Gui, *gocui.View) error) error {
+// createPromptPanel opens an editable popup. initialContent is written into the
+// prompt with the cursor placed after it, so the user can edit e.g. a default
+// commit message rather than typing it from scratch
+func (gui *Gui) createPromptPanel(g *gocui.Gui, currentView *gocui.View, title string, initialContent string, handleConfirm func(*gocui.Gui, *gocui.View) error) error {
 	gui.onNewPopupPanel()
-	confirmationView, err := gui.prepareConfirmationPanel(currentView, title, "", false)
+	confirmationView, err := gui.prepareConfirmationPanel(currentView, title, initialContent, false)
 	if err != nil {
 		return err
 	}
 	confirmationView.Editable = true
+	if initialContent != "" {
+		confirmationView.Clear()
+		fmt.Fprint(confirmationView, initialContent)
+		_ = confirmationView.SetCursor(len([]rune(initialContent)), 0)
+	}
 	return gui.setKeyBindings(g, handleConfirm, nil)
 }
 
+// createSuggestionsPromptPanel opens a prompt in which pressing tab completes
+// what has been typed so far to the next matching suggestion. The matching
+// suggestions are listed in the title as the user types
+func (gui *Gui) createSuggestionsPromptPanel(g *gocui.Gui, currentView *gocui.View, title string, initialContent string, suggestions []string, handleConfirm func(*gocui.Gui, *gocui.View) error) error {
+	if err := gui.createPromptPanel(g, currentView, title, initialContent, handleConfirm); err != nil {
+		return err
+	}
+	confirmationView, err := g.View("confirmation")
+	if err != nil {
+		return err
+	}
+	confirmationView.Editor = gui.suggestionsEditor(title, suggestions)
+	confirmationView.Title = suggestionsTitle(title, suggestions, initialContent)
+	return nil
+}
+
+func (gui *Gui) suggestionsEditor(title string, suggestions []string) gocui.Editor {
+	// prefix is what the user had typed before they started cycling through
+	// the suggestions with tab
+	prefix := ""
+	cycling := false
+
+	return gocui.EditorFunc(func(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+		if key != gocui.KeyTab {
+			cycling = false
+			gocui.DefaultEditor.Edit(v, key, ch, mod)
+			v.Title = suggestionsTitle(title, suggestions, gui.trimmedContent(v))
+			return
+		}
+
+		content := gui.trimmedContent(v)
+		if !cycling {
+			prefix = content
+			cycling = true
+		}
+		matches := matchingSuggestions(suggestions, prefix)
+		if len(matches) == 0 {
+			return
+		}
+		next := matches[0]
+		for i, match := range matches {
+			if match == content {
+				next = matches[(i+1)%len(matches)]
+				break
+			}
+		}
+		v.Clear()
+		fmt.Fprint(v, next)
+		_ = v.SetCursor(len([]rune(next)), 0)
+	})
+}
+
+func matchingSuggestions(suggestions []string, prefix string) []string {
+	matches := []string{}
+	for _, suggestion := range suggestions {
+		if strings.HasPrefix(suggestion, prefix) {
+			matches = append(matches, suggestion)
+		}
+	}
+	return matches
+}
+
+func suggestionsTitle(title string, suggestions []string, content string) string {
+	matches := matchingSuggestions(suggestions, content)
+	if len(matches) == 0 {
+		return title
+	}
+	return fmt.Sprintf("%s (%s)", title, strings.Join(matches, ", "))
+}
+
 func (gui *Gui) prepareConfirmationPanel(currentView *gocui.View, title, prompt string, hasLoader bool) (*gocui.View, error) {
 	x0, y0, x1, y1 := gui.getConfirmationPanelDimensions(gui.g, true, prompt)
 	confirmationView, err := gui.g.SetView("confirmation", x0, y0, x1, y1, 0)
@@ -92,6 +171,7 @@ func (gui *Gui) prepareConfirmationPanel(currentView *gocui.View, title, prompt
 
 func (gui *Gui) onNewPopupPanel() {
 	viewNames := []string{"commitMessage",
+		"commitDescription",
 		"credentials",
 		"menu"}
 	for _, viewName := range viewNames {
@@ -140,17 +220,17 @@ func (gui *Gui) setKeyBindings(g *gocui.Gui, handleConfirm, handleClose func(*go
 	actions := gui.Tr.TemplateLocalize(
 		"CloseConfirm",
 		Teml{
-			"keyBindClose":   "esc",
-			"keyBindConfirm": "enter",
+			"keyBindClose":   gui.getKeyDisplay("universal.return"),
+			"keyBindConfirm": gui.getKeyDisplay("universal.confirm"),
 		},
 	)
 	if err := gui.renderString(g, "options", actions); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("confirmation", gocui.KeyEnter, gocui.ModNone, gui.wrappedConfirmationFunction(handleConfirm)); err != nil {
+	if err := gui.setNamedKeybindings("confirmation", []string{"universal.confirm"}, gui.wrappedConfirmationFunction(handleConfirm)); err != nil {
 		return err
 	}
-	return g.SetKeybinding("confirmation", gocui.KeyEsc, gocui.ModNone, gui.wrappedConfirmationFunction(handleClose))
+	return gui.setNamedKeybindings("confirmation", []string{"universal.return"}, gui.wrappedConfirmationFunction(handleClose))
 }
 
 func (gui *Gui) createMessagePanel(g *gocui.Gui, currentView *gocui.View, title, prompt string) error {
@@ -34,6 +34,10 @@ func (gui *Gui) setMainTitle() error {
 	} else {
 		return nil
 	}
+	if gui.State.Search != nil && gui.State.Search.ViewName == "main" {
+		gui.State.Search.BaseTitle = newTitle
+		newTitle = gui.searchTitle()
+	}
 	gui.getMainView().Title = newTitle
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
 )
 
 type credentials chan string
@@ -13,10 +14,22 @@ func (gui *Gui) waitForPassUname(g *gocui.Gui, currentView *gocui.View, passOrUn
 	gui.credentials = make(chan string)
 	g.Update(func(g *gocui.Gui) error {
 		credentialsView, _ := g.View("credentials")
-		if passOrUname == "username" {
+		switch passOrUname {
+		case commands.CredentialUsername:
 			credentialsView.Title = gui.Tr.SLocalize("CredentialsUsername")
 			credentialsView.Mask = 0
-		} else {
+		case commands.CredentialPassphrase:
+			credentialsView.Title = gui.Tr.SLocalize("CredentialsPassphrase")
+			credentialsView.Mask = '*'
+		case commands.CredentialHostKey:
+			credentialsView.Title = gui.Tr.SLocalize("CredentialsHostKey")
+			credentialsView.Mask = 0
+		case commands.CredentialOTP:
+			// one-time passwords expire straight away so there's no harm in
+			// showing them, and it makes typos easier to spot
+			credentialsView.Title = gui.Tr.SLocalize("CredentialsOTP")
+			credentialsView.Mask = 0
+		default:
 			credentialsView.Title = gui.Tr.SLocalize("CredentialsPassword")
 			credentialsView.Mask = '*'
 		}
@@ -51,7 +64,10 @@ func (gui *Gui) handleSubmitCredential(g *gocui.Gui, v *gocui.View) error {
 	}
 	nextView, err := gui.g.View("confirmation")
 	if err != nil {
-		nextView = gui.getFilesView()
+		nextView, err = gui.g.View("hookOutput")
+		if err != nil {
+			nextView = gui.getFilesView()
+		}
 	}
 	err = gui.switchFocus(g, nil, nextView)
 	if err != nil {
@@ -78,8 +94,8 @@ func (gui *Gui) handleCredentialsViewFocused(g *gocui.Gui, v *gocui.View) error
 	message := gui.Tr.TemplateLocalize(
 		"CloseConfirm",
 		Teml{
-			"keyBindClose":   "esc",
-			"keyBindConfirm": "enter",
+			"keyBindClose":   gui.getKeyDisplay("universal.return"),
+			"keyBindConfirm": gui.getKeyDisplay("universal.confirm"),
 		},
 	)
 	return gui.renderString(g, "options", message)
@@ -0,0 +1,261 @@
+package gui
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+)
+
+// CustomCommand is an entry of the customCommands section of the user config.
+// Command, and the titles and initial values of its prompts, are templates
+// that can refer to the selected items (see customCommandObjects) and to the
+// answers given to the prompts so far
+type CustomCommand struct {
+	Key         string
+	Context     string
+	Command     string
+	Description string
+	Subprocess  bool
+	LoadingText string
+	Prompts     []CustomCommandPrompt
+}
+
+// CustomCommandPrompt asks for one of a custom command's arguments, either as
+// free text (type: input) or by picking from a list of options (type: menu)
+type CustomCommandPrompt struct {
+	Type         string
+	Title        string
+	InitialValue string
+	Options      []CustomCommandMenuOption
+}
+
+// CustomCommandMenuOption is an option of a menu prompt. Value is what the
+// prompt's answer is when the option is picked
+type CustomCommandMenuOption struct {
+	Name        string
+	Description string
+	Value       string
+}
+
+// GetDisplayStrings is a function.
+func (o *CustomCommandMenuOption) GetDisplayStrings(isFocused bool) []string {
+	name := o.Name
+	if name == "" {
+		name = o.Value
+	}
+	if o.Description == "" {
+		return []string{name}
+	}
+	return []string{name, o.Description}
+}
+
+// customCommandObjects is what custom command templates are executed against,
+// so e.g. {{.SelectedCommit.Sha}} gives the sha of the selected commit and
+// {{index .PromptResponses 0}} gives the answer to the first prompt
+type customCommandObjects struct {
+	SelectedFile       *commands.File
+	SelectedBranch     *commands.Branch
+	SelectedCommit     *commands.Commit
+	SelectedStashEntry *commands.StashEntry
+	CheckedOutBranch   *commands.Branch
+	PromptResponses    []string
+}
+
+// the contexts a custom command can be bound in, and the views they map to
+var customCommandContextViews = map[string]string{
+	"global":   "",
+	"files":    "files",
+	"branches": "branches",
+	"commits":  "commits",
+	"stash":    "stash",
+}
+
+func (gui *Gui) getCustomCommands() []CustomCommand {
+	customCommands := []CustomCommand{}
+	if err := gui.Config.GetUserConfig().UnmarshalKey("customCommands", &customCommands); err != nil {
+		gui.Log.Error(err)
+		return nil
+	}
+	return customCommands
+}
+
+// getCustomCommandKeybindings returns a binding for each of the user's custom
+// commands. Entries with an unknown context or key are logged and skipped
+func (gui *Gui) getCustomCommandKeybindings() []*Binding {
+	bindings := []*Binding{}
+	for _, customCommand := range gui.getCustomCommands() {
+		viewName, ok := customCommandContextViews[customCommand.Context]
+		if !ok {
+			gui.Log.Errorf("custom command %q has an unknown context %q", customCommand.Command, customCommand.Context)
+			continue
+		}
+		key, err := parseKey(customCommand.Key)
+		if err != nil {
+			gui.Log.Errorf("custom command %q: %v", customCommand.Command, err)
+			continue
+		}
+		if key == nil {
+			continue // the key is <disabled>
+		}
+		description := customCommand.Description
+		if description == "" {
+			description = customCommand.Command
+		}
+		bindings = append(bindings, &Binding{
+			ViewName:    viewName,
+			Key:         key,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCustomCommandKeybinding(customCommand),
+			Description: description,
+		})
+	}
+	return bindings
+}
+
+func (gui *Gui) getCustomCommandObjects(promptResponses []string) *customCommandObjects {
+	objects := &customCommandObjects{
+		SelectedBranch:     gui.getSelectedBranch(),
+		SelectedCommit:     gui.getSelectedCommit(gui.g),
+		SelectedStashEntry: gui.getSelectedStashEntry(nil),
+		PromptResponses:    promptResponses,
+	}
+	if file, err := gui.getSelectedFile(gui.g); err == nil {
+		objects.SelectedFile = file
+	}
+	if len(gui.State.Branches) > 0 {
+		objects.CheckedOutBranch = gui.State.Branches[0]
+	}
+	return objects
+}
+
+// resolveCustomCommandTemplate fills in a custom command's template. Names
+// and answers to prompts can hold anything, so a command should pass them
+// through quote before handing them to the shell
+func (gui *Gui) resolveCustomCommandTemplate(templateStr string, promptResponses []string) (string, error) {
+	funcs := template.FuncMap{
+		"quote": gui.OSCommand.ShellQuote,
+	}
+	tmpl, err := template.New("").Funcs(funcs).Parse(templateStr)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, gui.getCustomCommandObjects(promptResponses)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// handleCustomCommandKeybinding returns a handler that goes through the custom
+// command's prompts one after the other before running it
+func (gui *Gui) handleCustomCommandKeybinding(customCommand CustomCommand) func(*gocui.Gui, *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		promptResponses := make([]string, len(customCommand.Prompts))
+
+		f := func() error {
+			return gui.runCustomCommand(customCommand, promptResponses)
+		}
+
+		// we build the chain from the end, so that each prompt calls the next
+		// one once it's been answered and the last one runs the command
+		for i := len(customCommand.Prompts) - 1; i >= 0; i-- {
+			index := i
+			prompt := customCommand.Prompts[i]
+			next := f
+
+			switch prompt.Type {
+			case "input":
+				f = func() error {
+					return gui.handleCustomCommandInputPrompt(v, prompt, promptResponses, index, next)
+				}
+			case "menu":
+				f = func() error {
+					return gui.handleCustomCommandMenuPrompt(prompt, promptResponses, index, next)
+				}
+			default:
+				return gui.createErrorPanel(g, gui.Tr.TemplateLocalize(
+					"InvalidCustomCommandPromptType",
+					Teml{
+						"type": prompt.Type,
+					},
+				))
+			}
+		}
+
+		return f()
+	}
+}
+
+func (gui *Gui) handleCustomCommandInputPrompt(v *gocui.View, prompt CustomCommandPrompt, promptResponses []string, index int, next func() error) error {
+	title, err := gui.resolveCustomCommandTemplate(prompt.Title, promptResponses)
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+	initialValue, err := gui.resolveCustomCommandTemplate(prompt.InitialValue, promptResponses)
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	return gui.createPromptPanel(gui.g, v, title, initialValue, func(g *gocui.Gui, v *gocui.View) error {
+		promptResponses[index] = gui.trimmedContent(v)
+		// the prompt is only closed once we return, so anything that opens a
+		// new popup has to wait until then
+		g.Update(func(g *gocui.Gui) error {
+			return next()
+		})
+		return nil
+	})
+}
+
+func (gui *Gui) handleCustomCommandMenuPrompt(prompt CustomCommandPrompt, promptResponses []string, index int, next func() error) error {
+	if len(prompt.Options) == 0 {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("CustomCommandMenuWithoutOptions"))
+	}
+	title, err := gui.resolveCustomCommandTemplate(prompt.Title, promptResponses)
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	options := make([]*CustomCommandMenuOption, len(prompt.Options))
+	for i := range prompt.Options {
+		options[i] = &prompt.Options[i]
+	}
+
+	handleMenuPress := func(optionIndex int) error {
+		promptResponses[index] = options[optionIndex].Value
+		gui.g.Update(func(g *gocui.Gui) error {
+			return next()
+		})
+		return nil
+	}
+
+	return gui.createMenu(title, options, len(options), handleMenuPress)
+}
+
+// runCustomCommand runs the custom command once all its prompts are answered,
+// either as a subprocess, handing the terminal over to it, or in the
+// background, in which case we refresh once it's done
+func (gui *Gui) runCustomCommand(customCommand CustomCommand, promptResponses []string) error {
+	cmdStr, err := gui.resolveCustomCommandTemplate(customCommand.Command, promptResponses)
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	if customCommand.Subprocess {
+		gui.SubProcess = gui.OSCommand.RunCustomCommand(cmdStr)
+		return gui.Errors.ErrSubProcess
+	}
+
+	loadingText := customCommand.LoadingText
+	if loadingText == "" {
+		loadingText = gui.Tr.SLocalize("RunningCustomCommandStatus")
+	}
+	return gui.WithWaitingStatus(loadingText, func(git *commands.GitCommand) error {
+		if _, err := git.OSCommand.RunDirectCommand(cmdStr); err != nil {
+			return err
+		}
+		return gui.refreshSidePanels(gui.g)
+	})
+}
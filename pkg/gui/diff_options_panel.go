@@ -0,0 +1,121 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/gocui"
+)
+
+type diffOption struct {
+	description string
+	value       string
+	handle      func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *diffOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description, color.New(color.FgYellow).Sprint(o.value)}
+}
+
+func (gui *Gui) onOffDisplay(on bool) string {
+	if on {
+		return gui.Tr.SLocalize("optionOn")
+	}
+	return gui.Tr.SLocalize("optionOff")
+}
+
+func (gui *Gui) thresholdDisplay(threshold int) string {
+	if threshold == 0 {
+		return gui.Tr.SLocalize("gitDefault")
+	}
+	return fmt.Sprintf("%d%%", threshold)
+}
+
+func (gui *Gui) handleCreateDiffOptionsMenu(g *gocui.Gui, v *gocui.View) error {
+	diffOptions := gui.getRepoState().DiffOptions
+
+	options := []*diffOption{
+		{
+			description: gui.Tr.SLocalize("increaseContextLines"),
+			value:       strconv.Itoa(diffOptions.ContextLines),
+			handle: func() error {
+				diffOptions.ContextLines++
+				return gui.onDiffOptionsChanged()
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("decreaseContextLines"),
+			value:       strconv.Itoa(diffOptions.ContextLines),
+			handle: func() error {
+				if diffOptions.ContextLines > 0 {
+					diffOptions.ContextLines--
+				}
+				return gui.onDiffOptionsChanged()
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("toggleIgnoreWhitespace"),
+			value:       gui.onOffDisplay(diffOptions.IgnoreWhitespace),
+			handle: func() error {
+				diffOptions.IgnoreWhitespace = !diffOptions.IgnoreWhitespace
+				return gui.onDiffOptionsChanged()
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("toggleWordDiff"),
+			value:       gui.onOffDisplay(diffOptions.WordDiff),
+			handle: func() error {
+				diffOptions.WordDiff = !diffOptions.WordDiff
+				return gui.onDiffOptionsChanged()
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("setRenameThreshold"),
+			value:       gui.thresholdDisplay(diffOptions.RenameThreshold),
+			handle: func() error {
+				return gui.promptForThreshold(g, v, &diffOptions.RenameThreshold)
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("setCopyThreshold"),
+			value:       gui.thresholdDisplay(diffOptions.CopyThreshold),
+			handle: func() error {
+				return gui.promptForThreshold(g, v, &diffOptions.CopyThreshold)
+			},
+		},
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].handle()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("DiffOptionsTitle"), options, len(options), handleMenuPress)
+}
+
+// promptForThreshold asks for the similarity percentage used to detect renames
+// or copies, where 0 leaves it up to git
+func (gui *Gui) promptForThreshold(g *gocui.Gui, v *gocui.View, threshold *int) error {
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("ThresholdPrompt"), strconv.Itoa(*threshold), func(g *gocui.Gui, v *gocui.View) error {
+		value, err := strconv.Atoi(strings.TrimSpace(gui.trimmedContent(v)))
+		if err != nil || value < 0 || value > 100 {
+			return gui.createErrorPanel(g, gui.Tr.SLocalize("InvalidThreshold"))
+		}
+		*threshold = value
+		return gui.onDiffOptionsChanged()
+	})
+}
+
+// onDiffOptionsChanged saves the repo's diff options and shows the staging
+// panel's diff again if that's what's up. Everything else is re-rendered
+// when focus goes back to the side panel the diff options were changed from
+func (gui *Gui) onDiffOptionsChanged() error {
+	if gui.State.Contexts["main"] == "staging" {
+		gui.g.Update(func(*gocui.Gui) error {
+			return gui.refreshStagingPanel()
+		})
+	}
+	return gui.Config.SaveAppState()
+}
@@ -0,0 +1,206 @@
+package gui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// the longest we hold on to a diff's output before showing what's come in, so
+// that a big diff is shown in a few goes rather than a chunk at a time
+const diffStreamInterval = 50 * time.Millisecond
+
+// showDiff shows a diff in the main view. Without a pager we run the commands
+// that make it up ourselves, showing their output as it comes in. A pager
+// needs the whole diff at once, so then we get it with load in the background.
+// Either way a diff bigger than git.maxDiffSize is cut short. keepOrigin is
+// set when we're showing the same thing again, e.g. after a refresh
+func (gui *Gui) showDiff(commands func() []string, load func() (string, error), keepOrigin bool) {
+	if !gui.GitCommand.UsingPager() {
+		gui.streamDiff(commands, keepOrigin)
+		return
+	}
+
+	gui.loadInBackground("main", func() (func(*gocui.Gui) error, error) {
+		diff, err := load()
+		return func(g *gocui.Gui) error {
+			v := gui.getMainView()
+			if v == nil {
+				return nil
+			}
+			if err != nil {
+				return gui.setViewContent(g, v, err.Error())
+			}
+			if !keepOrigin {
+				if err := gui.resetWindow(v); err != nil {
+					return err
+				}
+			}
+			maxSize := gui.maxDiffSize()
+			if maxSize > 0 && len(diff) > maxSize {
+				diff = diff[:strings.LastIndex(diff[:maxSize], "\n")+1] + gui.diffTruncatedNote()
+			}
+			return gui.setDiffContent(v, diff)
+		}, nil
+	})
+}
+
+// maxDiffSize returns how many bytes of a diff we show, or 0 for all of it
+func (gui *Gui) maxDiffSize() int {
+	return utils.Max(0, gui.Config.GetUserConfig().GetInt("git.maxDiffSize")*1024*1024)
+}
+
+func (gui *Gui) diffTruncatedNote() string {
+	note := gui.Tr.TemplateLocalize("DiffTruncated", Teml{
+		"size": gui.Config.GetUserConfig().GetInt("git.maxDiffSize"),
+	})
+	return color.New(color.FgYellow).Sprint(note) + "\n"
+}
+
+// streamDiff runs the commands that make up a diff on a background worker,
+// showing their output in the main view as it comes in. Anything else being
+// put in the main view stops it
+func (gui *Gui) streamDiff(commands func() []string, keepOrigin bool) {
+	stream := &diffStream{
+		gui:        gui,
+		loadNumber: gui.startBackgroundLoad("main"),
+		keepOrigin: keepOrigin,
+		maxSize:    gui.maxDiffSize(),
+	}
+	go stream.run(commands)
+}
+
+// diffStream is a diff whose output is being shown in the main view as it
+// comes in
+type diffStream struct {
+	gui        *Gui
+	loadNumber int
+	// keepOrigin is set when we're showing what's there already again, in
+	// which case we wait for all of the diff rather than lose the user's place
+	keepOrigin bool
+	maxSize    int
+
+	output    strings.Builder
+	truncated bool
+	started   bool
+	// how much of the output we've handed to the main view, and when
+	shown     int
+	lastShown time.Time
+}
+
+func (s *diffStream) isLatest() bool {
+	return s.gui.isLatestBackgroundLoad("main", s.loadNumber)
+}
+
+func (s *diffStream) run(commands func() []string) {
+	var firstErr error
+	for _, command := range commands() {
+		err := s.gui.OSCommand.StreamCommandOutput(command, s.onOutput)
+		if !s.isLatest() {
+			return
+		}
+		if err != nil && firstErr == nil {
+			// e.g. git diff --no-index fails whenever there's a difference,
+			// so an error only matters if there's nothing else to show
+			firstErr = err
+		}
+		if s.truncated {
+			break
+		}
+	}
+
+	if firstErr != nil && s.output.Len() == 0 {
+		s.gui.g.Update(func(g *gocui.Gui) error {
+			v := s.gui.getMainView()
+			if v == nil || !s.isLatest() {
+				return nil
+			}
+			return s.gui.setViewContent(g, v, firstErr.Error())
+		})
+		return
+	}
+	s.show(true)
+}
+
+// onOutput is handed the output of the commands as it comes in, returning
+// false once it's not wanted anymore
+func (s *diffStream) onOutput(output string) bool {
+	if !s.isLatest() {
+		return false
+	}
+	if s.maxSize > 0 && s.output.Len()+len(output) > s.maxSize {
+		output = output[:s.maxSize-s.output.Len()]
+		s.truncated = true
+	}
+	s.output.WriteString(output)
+	if s.truncated {
+		return false
+	}
+	if !s.keepOrigin && time.Since(s.lastShown) >= diffStreamInterval {
+		s.show(false)
+	}
+	return true
+}
+
+// show hands what's come in since last time to the main view. Until we're done
+// that's up to the end of the last whole line
+func (s *diffStream) show(done bool) {
+	diff := s.output.String()
+	if !done || s.truncated {
+		diff = diff[:strings.LastIndex(diff, "\n")+1]
+	}
+	if done && s.truncated {
+		diff += s.gui.diffTruncatedNote()
+	}
+	if !done && len(diff) == s.shown {
+		return
+	}
+	first := !s.started
+	newOutput := diff[s.shown:]
+	s.started = true
+	s.shown = len(diff)
+	s.lastShown = time.Now()
+
+	s.gui.g.Update(func(g *gocui.Gui) error {
+		if !s.isLatest() {
+			return nil
+		}
+		v := s.gui.getMainView()
+		if v == nil {
+			return nil
+		}
+		if !first {
+			return s.gui.appendDiffContent(v, diff, newOutput)
+		}
+		if !s.keepOrigin {
+			if err := s.gui.resetWindow(v); err != nil {
+				return err
+			}
+		}
+		return s.gui.setDiffContent(v, diff)
+	})
+}
+
+// appendDiffContent adds the latest output of a diff that's coming in to the
+// main view, which only writes it to the view if it's near what's on screen
+func (gui *Gui) appendDiffContent(v *gocui.View, diff string, output string) error {
+	if gui.State.SideBySideDiff {
+		// the two sides of a hunk are laid out together, so we lay out all of
+		// it again
+		return gui.setDiffContent(v, diff)
+	}
+	gui.State.MainDiff = diff
+	lines := gui.State.MainLines
+	if len(lines) > 0 {
+		// what we'd shown ended with a newline, after which there's an empty
+		// line for the output to carry on from
+		lines = lines[:len(lines)-1]
+	}
+	gui.State.MainLines = append(lines, strings.Split(gui.cleanString(output), "\n")...)
+	gui.updateSearchMatches(v, gui.State.MainLines)
+	gui.refreshMainWindow(v)
+	return nil
+}
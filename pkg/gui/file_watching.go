@@ -0,0 +1,367 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-errors/errors"
+	"github.com/jesseduffield/gocui"
+)
+
+// how long we wait for things to settle down after a change before
+// refreshing, given that e.g. a checkout touches lots of files at once
+const fileWatcherDebounce = 200 * time.Millisecond
+
+// the longest we put off a refresh for while changes keep coming in
+const fileWatcherMaxDelay = 2 * time.Second
+
+var errTooManyDirectories = errors.New("too many directories to watch")
+
+// refreshScope is what a batch of changes means we need to refresh
+type refreshScope struct {
+	// index is set when the index has changed, which may only be git status
+	// writing to it, so it's left to refresh to decide whether the files need
+	// refreshing
+	index    bool
+	files    bool
+	branches bool
+	commits  bool
+	stash    bool
+}
+
+// fileWatcher watches the working tree and the .git directory for changes made
+// outside of lazygit, e.g. in an editor, and refreshes the panels that they
+// affect. Directories that git ignores, like build outputs, aren't watched
+type fileWatcher struct {
+	gui                   *Gui
+	watcher               *fsnotify.Watcher
+	dotGitDir             string
+	ignoredDirectories    map[string]bool
+	watchedDirectories    int
+	maxWatchedDirectories int
+	pollInterval          time.Duration
+
+	// stopPolling is set once we've had to fall back to polling
+	mutex       sync.Mutex
+	stopPolling func()
+}
+
+// startFileWatcher starts refreshing the panels when something changes in the
+// repo, falling back to polling the files if the repo can't be watched, e.g.
+// because it would take more watchers than the OS allows. It returns a function
+// that stops it again
+func (gui *Gui) startFileWatcher() func() {
+	userConfig := gui.Config.GetUserConfig()
+	pollInterval := time.Duration(userConfig.GetInt("refresher.pollInterval")) * time.Second
+	if !userConfig.GetBool("refresher.watchFiles") {
+		return gui.startPolling(pollInterval)
+	}
+
+	w := &fileWatcher{
+		gui:                   gui,
+		dotGitDir:             gui.GitCommand.DotGitDir,
+		ignoredDirectories:    map[string]bool{},
+		maxWatchedDirectories: userConfig.GetInt("refresher.maxWatchedDirectories"),
+		pollInterval:          pollInterval,
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		gui.Log.Error(err)
+		return gui.startPolling(pollInterval)
+	}
+	w.watcher = watcher
+
+	// walking a big working tree takes a while, so we get on with it in the
+	// background
+	go func() {
+		if err := w.watchRepo(); err != nil {
+			gui.Log.Warn(err)
+			w.fallBackToPolling()
+			return
+		}
+		w.run()
+	}()
+	return w.close
+}
+
+// startPolling refreshes the files every so often, returning a function that
+// stops it
+func (gui *Gui) startPolling(interval time.Duration) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+	ticker := time.NewTicker(interval)
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				gui.refreshFilesInBackground()
+			case <-stop:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(stop) }
+}
+
+func (w *fileWatcher) close() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.stopPolling != nil {
+		w.stopPolling()
+		w.stopPolling = nil
+	}
+	_ = w.watcher.Close()
+}
+
+func (w *fileWatcher) fallBackToPolling() {
+	w.gui.Log.Warn("falling back to polling for changes to the repo")
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	_ = w.watcher.Close()
+	if w.stopPolling == nil {
+		w.stopPolling = w.gui.startPolling(w.pollInterval)
+	}
+}
+
+// watchRepo watches every directory in the working tree that isn't ignored,
+// along with the parts of the .git directory that tell us about the index,
+// HEAD and refs
+func (w *fileWatcher) watchRepo() error {
+	ignoredDirectories, err := w.gui.GitCommand.IgnoredDirectories()
+	if err != nil {
+		return err
+	}
+	for _, directory := range ignoredDirectories {
+		w.ignoredDirectories[filepath.Clean(directory)] = true
+	}
+
+	directories, err := w.directoriesToWatch(".")
+	if err != nil {
+		return err
+	}
+	if err := w.addWatches(directories); err != nil {
+		return err
+	}
+
+	gitDirectories := []string{w.dotGitDir}
+	refDirectories, err := w.directoriesToWatch(filepath.Join(w.dotGitDir, "refs"))
+	if err != nil {
+		return err
+	}
+	return w.addWatches(append(gitDirectories, refDirectories...))
+}
+
+// directoriesToWatch returns the given directory and the ones below it, minus
+// .git directories and any that git ignores
+func (w *fileWatcher) directoriesToWatch(root string) ([]string, error) {
+	directories := []string{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// it may have been deleted while we were walking
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root && (info.Name() == ".git" || w.ignoredDirectories[filepath.Clean(path)]) {
+			return filepath.SkipDir
+		}
+		directories = append(directories, path)
+		if w.watchedDirectories+len(directories) > w.maxWatchedDirectories {
+			return errTooManyDirectories
+		}
+		return nil
+	})
+	return directories, err
+}
+
+func (w *fileWatcher) addWatches(directories []string) error {
+	if w.watchedDirectories+len(directories) > w.maxWatchedDirectories {
+		return errTooManyDirectories
+	}
+	for _, directory := range directories {
+		if err := w.watcher.Add(directory); err != nil {
+			return err
+		}
+		w.watchedDirectories++
+	}
+	return nil
+}
+
+func (w *fileWatcher) isInDotGitDir(path string) bool {
+	rel, err := filepath.Rel(w.dotGitDir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// run waits for changes, refreshing once they've settled down
+func (w *fileWatcher) run() {
+	scope := refreshScope{}
+	changedPaths := map[string]bool{}
+	var timer <-chan time.Time
+	var firstChange time.Time
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if !w.handleEvent(event, &scope, changedPaths) {
+				continue
+			}
+			if timer == nil {
+				firstChange = time.Now()
+			}
+			// we hold off while changes keep coming in, but not forever
+			delay := fileWatcherDebounce
+			if remaining := fileWatcherMaxDelay - time.Since(firstChange); remaining < delay {
+				delay = remaining
+			}
+			timer = time.After(delay)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.gui.Log.Error(err)
+			if err == fsnotify.ErrEventOverflow {
+				// we've missed some changes, so we can't tell what to refresh
+				scope = refreshScope{files: true, branches: true, commits: true, stash: true}
+				timer = time.After(fileWatcherDebounce)
+			}
+		case <-timer:
+			w.refresh(scope, changedPaths)
+			scope = refreshScope{}
+			changedPaths = map[string]bool{}
+			timer = nil
+		}
+	}
+}
+
+// handleEvent works out what needs refreshing after a change, returning false
+// if nothing does. Changes in the working tree are kept in changedPaths so
+// that we can leave out the ones git ignores
+func (w *fileWatcher) handleEvent(event fsnotify.Event, scope *refreshScope, changedPaths map[string]bool) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	path := filepath.Clean(event.Name)
+	inDotGitDir := w.isInDotGitDir(path)
+	if event.Op&fsnotify.Create != 0 {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			w.watchNewDirectory(path, inDotGitDir)
+		}
+	}
+	if inDotGitDir {
+		return w.handleDotGitEvent(path, scope)
+	}
+	changedPaths[path] = true
+	return true
+}
+
+func (w *fileWatcher) handleDotGitEvent(path string, scope *refreshScope) bool {
+	rel, _ := filepath.Rel(w.dotGitDir, path)
+	rel = filepath.ToSlash(rel)
+	switch {
+	case strings.HasSuffix(rel, ".lock"):
+		// git is still busy, and we'll hear about whatever it's locked when
+		// it's done
+		return false
+	case rel == "index":
+		scope.index = true
+	case rel == "HEAD" || rel == "packed-refs" || strings.HasPrefix(rel, "refs/heads"):
+		scope.branches = true
+		scope.commits = true
+	case rel == "FETCH_HEAD" || strings.HasPrefix(rel, "refs/remotes"):
+		// the counts of commits to push and pull have changed
+		scope.branches = true
+		scope.commits = true
+	case strings.HasPrefix(rel, "refs/stash"):
+		scope.stash = true
+	case strings.HasPrefix(rel, "refs/tags"):
+		scope.commits = true
+	case rel == "MERGE_HEAD" || rel == "CHERRY_PICK_HEAD" || strings.HasPrefix(rel, "rebase-merge") || strings.HasPrefix(rel, "rebase-apply"):
+		// a merge or rebase has started or stopped
+		scope.files = true
+		scope.commits = true
+	default:
+		return false
+	}
+	return true
+}
+
+// watchNewDirectory watches a directory that's been created in the working
+// tree, unless git ignores it, or below .git/refs e.g. for a branch called
+// feature/foo. If that takes us over the limit we fall back to polling
+func (w *fileWatcher) watchNewDirectory(path string, inDotGitDir bool) {
+	if inDotGitDir {
+		rel, _ := filepath.Rel(w.dotGitDir, path)
+		if !strings.HasPrefix(filepath.ToSlash(rel), "refs/") {
+			return
+		}
+	} else if len(w.gui.GitCommand.IgnoredPaths([]string{path})) > 0 {
+		w.ignoredDirectories[path] = true
+		return
+	}
+	directories, err := w.directoriesToWatch(path)
+	if err == nil {
+		err = w.addWatches(directories)
+	}
+	if err != nil {
+		w.gui.Log.Warn(err)
+		w.fallBackToPolling()
+	}
+}
+
+func (w *fileWatcher) refresh(scope refreshScope, changedPaths map[string]bool) {
+	if scope.index && w.gui.GitCommand.IndexChangedSinceStatus() {
+		scope.files = true
+	}
+	if !scope.files && len(changedPaths) > 0 {
+		// we don't want to refresh over and over while e.g. a build writes
+		// ignored files next to the tracked ones
+		paths := []string{}
+		for path := range changedPaths {
+			paths = append(paths, path)
+		}
+		ignored := map[string]bool{}
+		for _, path := range w.gui.GitCommand.IgnoredPaths(paths) {
+			ignored[filepath.Clean(path)] = true
+		}
+		for _, path := range paths {
+			if !ignored[path] && !w.ignoredDirectories[path] {
+				scope.files = true
+				break
+			}
+		}
+	}
+
+	if scope.files {
+		w.gui.refreshFilesInBackground()
+	}
+	if !scope.branches && !scope.commits && !scope.stash {
+		return
+	}
+	w.gui.g.Update(func(g *gocui.Gui) error {
+		if scope.branches {
+			if err := w.gui.refreshBranches(g); err != nil {
+				return err
+			}
+		}
+		if scope.commits {
+			if err := w.gui.refreshCommits(g); err != nil {
+				return err
+			}
+		}
+		if scope.stash {
+			return w.gui.refreshStashEntries(g)
+		}
+		return nil
+	})
+}
@@ -2,12 +2,10 @@ package gui
 
 import (
 
-	// "io"
 	// "io/ioutil"
 
-	// "strings"
-
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/fatih/color"
@@ -27,28 +25,20 @@ func (gui *Gui) getSelectedFile(g *gocui.Gui) (*commands.File, error) {
 	return gui.State.Files[selectedLine], nil
 }
 
+// handleFilesFocus selects the file that was clicked on, or toggles whether
+// it's staged if it was already selected
 func (gui *Gui) handleFilesFocus(g *gocui.Gui, v *gocui.View) error {
 	if gui.popupPanelFocused() {
 		return nil
 	}
 
-	cx, cy := v.Cursor()
-	_, oy := v.Origin()
-
-	prevSelectedLine := gui.State.Panels.Files.SelectedLine
-	newSelectedLine := cy - oy
-
-	if newSelectedLine > len(gui.State.Files)-1 || len(utils.Decolorise(gui.State.Files[newSelectedLine].DisplayString)) < cx {
-		return gui.handleFileSelect(gui.g, v, false)
-	}
-
-	gui.State.Panels.Files.SelectedLine = newSelectedLine
-
-	if prevSelectedLine == newSelectedLine && gui.currentViewName() == v.Name() {
-		return gui.handleFilePress(gui.g, v)
-	} else {
-		return gui.handleFileSelect(gui.g, v, true)
+	cx, _ := v.Cursor()
+	index := gui.clickedItem(v)
+	if index != -1 && index == gui.State.Panels.Files.SelectedLine && gui.currentViewName() == v.Name() &&
+		cx <= len(utils.Decolorise(gui.State.Files[index].DisplayString)) {
+		return gui.handleFilePress(g, v)
 	}
+	return gui.handleListClick(g, v)
 }
 
 func (gui *Gui) handleFileSelect(g *gocui.Gui, v *gocui.View, alreadySelected bool) error {
@@ -72,14 +62,12 @@ func (gui *Gui) handleFileSelect(g *gocui.Gui, v *gocui.View, alreadySelected bo
 		return gui.refreshMergePanel()
 	}
 
-	content := gui.GitCommand.Diff(file, false)
-	if alreadySelected {
-		g.Update(func(*gocui.Gui) error {
-			return gui.setViewContent(gui.g, gui.getMainView(), content)
-		})
-		return nil
-	}
-	return gui.renderString(g, "main", content)
+	gui.showDiff(
+		func() []string { return []string{gui.GitCommand.DiffCommand(file, false, false)} },
+		func() (string, error) { return gui.GitCommand.Diff(file, false, false), nil },
+		alreadySelected,
+	)
+	return nil
 }
 
 func (gui *Gui) refreshFiles() error {
@@ -90,28 +78,60 @@ func (gui *Gui) refreshFiles() error {
 		// if the filesView hasn't been instantiated yet we just return
 		return nil
 	}
+	// whatever we're loading in the background is older than what we're about
+	// to load now
+	gui.dropBackgroundLoads("files")
 	if err := gui.refreshStateFiles(); err != nil {
 		return err
 	}
 
 	gui.g.Update(func(g *gocui.Gui) error {
+		return gui.renderFiles(selectedFile)
+	})
 
-		filesView.Clear()
-		isFocused := gui.g.CurrentView().Name() == "files"
-		list, err := utils.RenderList(gui.State.Files, isFocused)
-		if err != nil {
-			return err
-		}
-		fmt.Fprint(filesView, list)
+	return nil
+}
 
-		if filesView == g.CurrentView() {
-			newSelectedFile, _ := gui.getSelectedFile(gui.g)
-			alreadySelected := newSelectedFile.Name == selectedFile.Name
-			return gui.handleFileSelect(g, filesView, alreadySelected)
+// refreshFilesInBackground is refreshFiles for when nobody's waiting on the
+// files to be up to date, so that git status doesn't hold up the gui
+func (gui *Gui) refreshFilesInBackground() {
+	gui.loadInBackground("files", func() (func(*gocui.Gui) error, error) {
+		files := gui.getStatusFiles()
+		workingTreeState, err := gui.getWorkTreeState()
+		if err != nil {
+			// this happens behind the user's back, so rather than bother
+			// them with it we leave the files as they are until next time
+			gui.Log.Error(err)
+			return func(*gocui.Gui) error { return nil }, nil
 		}
-		return nil
+		return func(g *gocui.Gui) error {
+			if gui.getFilesView() == nil {
+				return nil
+			}
+			selectedFile, _ := gui.getSelectedFile(g)
+			gui.setStateFiles(files, workingTreeState)
+			return gui.renderFiles(selectedFile)
+		}, nil
 	})
+}
+
+// renderFiles renders the files panel, along with the main panel if the files
+// panel is focused. selectedFile is the file that was selected before the
+// files were refreshed
+func (gui *Gui) renderFiles(selectedFile *commands.File) error {
+	filesView := gui.getFilesView()
+	isFocused := gui.g.CurrentView().Name() == "files"
+	list, err := utils.RenderList(gui.State.Files, isFocused)
+	if err != nil {
+		return err
+	}
+	gui.renderListContent(filesView, list)
 
+	if filesView == gui.g.CurrentView() {
+		newSelectedFile, _ := gui.getSelectedFile(gui.g)
+		alreadySelected := newSelectedFile.Name == selectedFile.Name
+		return gui.handleFileSelect(gui.g, filesView, alreadySelected)
+	}
 	return nil
 }
 
@@ -121,7 +141,7 @@ func (gui *Gui) handleFilesNextLine(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	panelState := gui.State.Panels.Files
-	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.Files), false)
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, len(gui.State.Files), false)
 
 	return gui.handleFileSelect(gui.g, v, false)
 }
@@ -132,7 +152,7 @@ func (gui *Gui) handleFilesPrevLine(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	panelState := gui.State.Panels.Files
-	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.Files), true)
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, len(gui.State.Files), true)
 
 	return gui.handleFileSelect(gui.g, v, false)
 }
@@ -192,6 +212,20 @@ func (gui *Gui) handleEnterFile(g *gocui.Gui, v *gocui.View) error {
 	return gui.refreshStagingPanel()
 }
 
+// handleMainClick opens the selected file for staging, or for resolving its
+// merge conflicts, when the main panel is clicked while the files panel has
+// focus
+func (gui *Gui) handleMainClick(g *gocui.Gui, v *gocui.View) error {
+	if gui.currentViewName() != "files" {
+		return nil
+	}
+	file, err := gui.getSelectedFile(g)
+	if err != nil || !(file.HasInlineMergeConflicts || file.HasUnstagedChanges && !file.HasMergeConflicts) {
+		return nil
+	}
+	return gui.handleEnterFile(g, gui.getFilesView())
+}
+
 func (gui *Gui) handleFilePress(g *gocui.Gui, v *gocui.View) error {
 	file, err := gui.getSelectedFile(g)
 	if err != nil {
@@ -278,28 +312,48 @@ func (gui *Gui) handleIgnoreFile(g *gocui.Gui, v *gocui.View) error {
 	return gui.refreshFiles()
 }
 
+// handleWIPCommitPress opens the commit panels with hooks switched off, and the
+// message started with git.skipHookPrefix if it's configured
 func (gui *Gui) handleWIPCommitPress(g *gocui.Gui, filesView *gocui.View) error {
-	skipHookPreifx := gui.Config.GetUserConfig().GetString("git.skipHookPrefix")
-	if skipHookPreifx == "" {
-		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("SkipHookPrefixNotConfigured"))
+	if len(gui.stagedFiles()) == 0 && gui.State.WorkingTreeState == "normal" {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
 	}
 
-	if err := gui.renderString(g, "commitMessage", skipHookPreifx); err != nil {
-		return err
-	}
-	if err := gui.getCommitMessageView().SetCursor(len(skipHookPreifx), 0); err != nil {
+	// closing the commit panels switches the hooks back on
+	gui.State.Panels.CommitMessage.SkipHooks = true
+	if err := gui.handleCommitPress(g, filesView); err != nil {
+		gui.State.Panels.CommitMessage.SkipHooks = false
 		return err
 	}
 
-	return gui.handleCommitPress(g, filesView)
+	// the prefix goes in front of whatever draft or prefill the panels have
+	// been opened with
+	skipHookPrefix := gui.Config.GetUserConfig().GetString("git.skipHookPrefix")
+	message := gui.getCommitMessage()
+	if skipHookPrefix != "" && !strings.HasPrefix(message, skipHookPrefix) {
+		gui.setCommitMessage(skipHookPrefix + message)
+	}
+	return nil
 }
 
 func (gui *Gui) handleCommitPress(g *gocui.Gui, filesView *gocui.View) error {
 	if len(gui.stagedFiles()) == 0 && gui.State.WorkingTreeState == "normal" {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
 	}
+	if gui.getCommitMessage() == "" {
+		message := gui.getRepoState().CommitMessageDraft
+		if message == "" {
+			message = gui.getCommitMessagePrefill()
+		}
+		gui.setCommitMessage(message)
+	}
+	return gui.openCommitMessagePanel(g, filesView)
+}
+
+func (gui *Gui) openCommitMessagePanel(g *gocui.Gui, filesView *gocui.View) error {
 	commitMessageView := gui.getCommitMessageView()
 	g.Update(func(g *gocui.Gui) error {
+		g.SetViewOnTop("commitDescription")
 		g.SetViewOnTop("commitMessage")
 		gui.switchFocus(g, filesView, commitMessageView)
 		gui.RenderCommitLength()
@@ -308,6 +362,8 @@ func (gui *Gui) handleCommitPress(g *gocui.Gui, filesView *gocui.View) error {
 	return nil
 }
 
+// handleAmendCommitPress opens the commit panels with the message of the last
+// commit, so that the user can edit it as part of amending
 func (gui *Gui) handleAmendCommitPress(g *gocui.Gui, filesView *gocui.View) error {
 	if len(gui.stagedFiles()) == 0 && gui.State.WorkingTreeState == "normal" {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
@@ -316,20 +372,13 @@ func (gui *Gui) handleAmendCommitPress(g *gocui.Gui, filesView *gocui.View) erro
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoCommitToAmend"))
 	}
 
-	title := strings.Title(gui.Tr.SLocalize("AmendLastCommit"))
-	question := gui.Tr.SLocalize("SureToAmend")
-
-	return gui.createConfirmationPanel(g, filesView, title, question, func(g *gocui.Gui, v *gocui.View) error {
-		ok, err := gui.runSyncOrAsyncCommand(gui.GitCommand.AmendHead())
-		if err != nil {
-			return err
-		}
-		if !ok {
-			return nil
-		}
-
-		return gui.refreshSidePanels(g)
-	}, nil)
+	message, err := gui.GitCommand.GetCommitMessage("HEAD")
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	gui.State.Panels.CommitMessage.Amend = true
+	gui.setCommitMessage(message)
+	return gui.openCommitMessagePanel(g, filesView)
 }
 
 // handleCommitEditorPress - handle when the user wants to commit changes via
@@ -378,10 +427,69 @@ func (gui *Gui) handleRefreshFiles(g *gocui.Gui, v *gocui.View) error {
 
 func (gui *Gui) refreshStateFiles() error {
 	// get files to stage
-	files := gui.GitCommand.GetStatusFiles()
+	files := gui.getStatusFiles()
+	workingTreeState, err := gui.getWorkTreeState()
+	if err != nil {
+		return err
+	}
+	gui.setStateFiles(files, workingTreeState)
+	return nil
+}
+
+// getStatusFiles gets the files from git status. If we're scanning for
+// untracked files lazily it leaves them out, so that git doesn't have to walk
+// the working tree first. It doesn't touch the gui's state, so it can be
+// called from any goroutine
+func (gui *Gui) getStatusFiles() []*commands.File {
+	if gui.GitCommand.UntrackedFilesMode() == commands.UntrackedFilesLazy {
+		return gui.GitCommand.GetTrackedStatusFiles()
+	}
+	return gui.GitCommand.GetStatusFiles()
+}
+
+func (gui *Gui) setStateFiles(files []*commands.File, workingTreeState string) {
+	if gui.GitCommand.UntrackedFilesMode() == commands.UntrackedFilesLazy {
+		// we keep showing the untracked files we already know about until
+		// we've scanned for them again
+		files = append(files, untrackedFilesNotIn(gui.State.Files, files)...)
+		gui.scanForUntrackedFiles()
+	}
 	gui.State.Files = gui.GitCommand.MergeStatusFiles(gui.State.Files, files)
 	gui.refreshSelectedLine(&gui.State.Panels.Files.SelectedLine, len(gui.State.Files))
-	return gui.updateWorkTreeState()
+	gui.State.WorkingTreeState = workingTreeState
+}
+
+// scanForUntrackedFiles gets all of the files, untracked ones included, on a
+// background worker and shows them once it's done
+func (gui *Gui) scanForUntrackedFiles() {
+	gui.loadInBackground("untrackedFiles", func() (func(*gocui.Gui) error, error) {
+		files := gui.GitCommand.GetStatusFiles()
+		return func(g *gocui.Gui) error {
+			if gui.getFilesView() == nil {
+				return nil
+			}
+			selectedFile, _ := gui.getSelectedFile(g)
+			gui.State.Files = gui.GitCommand.MergeStatusFiles(gui.State.Files, files)
+			gui.refreshSelectedLine(&gui.State.Panels.Files.SelectedLine, len(gui.State.Files))
+			return gui.renderFiles(selectedFile)
+		}, nil
+	})
+}
+
+// untrackedFilesNotIn returns the untracked files in oldFiles that aren't in
+// newFiles, e.g. because they've since been staged
+func untrackedFilesNotIn(oldFiles, newFiles []*commands.File) []*commands.File {
+	names := map[string]bool{}
+	for _, file := range newFiles {
+		names[file.Name] = true
+	}
+	untracked := []*commands.File{}
+	for _, file := range oldFiles {
+		if file.ShortStatus == "??" && !names[file.Name] {
+			untracked = append(untracked, file)
+		}
+	}
+	return untracked
 }
 
 func (gui *Gui) catSelectedFile(g *gocui.Gui) (string, error) {
@@ -392,7 +500,7 @@ func (gui *Gui) catSelectedFile(g *gocui.Gui) (string, error) {
 		}
 		return "", gui.renderString(g, "main", gui.Tr.SLocalize("NoFilesDisplay"))
 	}
-	if item.Type != "file" {
+	if gui.OSCommand.FileType(item.Name) != "file" {
 		return "", gui.renderString(g, "main", gui.Tr.SLocalize("NotAFile"))
 	}
 	cat, err := gui.GitCommand.CatFile(item.Name)
@@ -404,49 +512,309 @@ func (gui *Gui) catSelectedFile(g *gocui.Gui) (string, error) {
 }
 
 func (gui *Gui) pullFiles(g *gocui.Gui, v *gocui.View) error {
+	return gui.pullWithMode(g, v, "")
+}
+
+// pullWithMode fetches from the checked out branch's remote and then brings the
+// branch up to date with its upstream. If mode is blank and the branches have
+// diverged we fall back to the repo's default pull mode, asking the user when
+// there is no default
+func (gui *Gui) pullWithMode(g *gocui.Gui, v *gocui.View, mode string) error {
 	if err := gui.createLoaderPanel(gui.g, v, gui.Tr.SLocalize("PullWait")); err != nil {
 		return err
 	}
 
 	go func() {
 		unamePassOpend := false
-		err := gui.GitCommand.Pull(func(passOrUname string) string {
-			unamePassOpend = true
-			return gui.waitForPassUname(g, v, passOrUname)
+		cancelled, err := gui.runTask(gui.Tr.SLocalize("PullWait"), func(git *commands.GitCommand) error {
+			return git.Fetch(func(passOrUname string) string {
+				unamePassOpend = true
+				return gui.waitForPassUname(g, v, passOrUname)
+			}, true)
+		})
+		if cancelled {
+			gui.closeCancelledLoader(unamePassOpend)
+			return
+		}
+		if err != nil {
+			gui.HandleCredentialsPopup(g, unamePassOpend, err)
+			return
+		}
+		if unamePassOpend {
+			_, _ = gui.g.SetViewOnBottom("credentials")
+		}
+		gui.g.Update(func(g *gocui.Gui) error {
+			return gui.reconcileWithUpstream(mode)
 		})
-		gui.HandleCredentialsPopup(g, unamePassOpend, err)
 	}()
 	return nil
 }
 
-func (gui *Gui) pushWithForceFlag(g *gocui.Gui, v *gocui.View, force bool) error {
-	if err := gui.createLoaderPanel(gui.g, v, gui.Tr.SLocalize("PushWait")); err != nil {
-		return err
+func (gui *Gui) reconcileWithUpstream(mode string) error {
+	pushables, pullables := gui.GitCommand.GetCurrentBranchUpstreamDifferenceCount()
+	if pullables == "?" {
+		_ = gui.closeConfirmationPrompt(gui.g)
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NoUpstreamForBranch"))
 	}
-	go func() {
-		unamePassOpend := false
-		branchName := gui.State.Branches[0].Name
-		err := gui.GitCommand.Push(branchName, force, func(passOrUname string) string {
-			unamePassOpend = true
+	if pullables == "0" {
+		_ = gui.closeConfirmationPrompt(gui.g)
+		return gui.refreshSidePanels(gui.g)
+	}
+
+	// with no local commits to reconcile, all we need to do is fast-forward
+	if pushables == "0" {
+		mode = commands.PullModeFastForwardOnly
+	}
+	if mode == "" {
+		mode = gui.getRepoState().PullMode
+	}
+	if mode != "" {
+		_ = gui.closeConfirmationPrompt(gui.g)
+		return gui.handleGenericMergeCommandResult(gui.GitCommand.ReconcileWithUpstream(mode))
+	}
+
+	_ = gui.closeConfirmationPrompt(gui.g)
+	title := gui.Tr.TemplateLocalize(
+		"BranchesHaveDiverged",
+		Teml{
+			"pushables": pushables,
+			"pullables": pullables,
+		},
+	)
+	options := gui.pullModeOptions()
+	handleMenuPress := func(index int) error {
+		return gui.handleGenericMergeCommandResult(gui.GitCommand.ReconcileWithUpstream(options[index].mode))
+	}
+	return gui.createMenu(title, options, len(options), handleMenuPress)
+}
+
+type pullModeOption struct {
+	description string
+	mode        string
+	command     string
+}
+
+// GetDisplayStrings is a function.
+func (o *pullModeOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description, color.New(color.FgYellow).Sprint(o.command)}
+}
+
+func (gui *Gui) pullModeOptions() []*pullModeOption {
+	return []*pullModeOption{
+		{
+			description: gui.Tr.SLocalize("pullModeMerge"),
+			mode:        commands.PullModeMerge,
+			command:     "git merge @{u}",
+		},
+		{
+			description: gui.Tr.SLocalize("pullModeRebase"),
+			mode:        commands.PullModeRebase,
+			command:     "git rebase @{u}",
+		},
+		{
+			description: gui.Tr.SLocalize("pullModeFastForwardOnly"),
+			mode:        commands.PullModeFastForwardOnly,
+			command:     "git merge --ff-only @{u}",
+		},
+	}
+}
+
+func (gui *Gui) handleCreatePullMenu(g *gocui.Gui, v *gocui.View) error {
+	options := append(gui.pullModeOptions(), &pullModeOption{
+		description: gui.Tr.SLocalize("setDefaultPullMode"),
+	})
+
+	handleMenuPress := func(index int) error {
+		if options[index].mode == "" {
+			return gui.handleCreateDefaultPullModeMenu(g, v)
+		}
+		return gui.pullWithMode(g, v, options[index].mode)
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("PullOptionsTitle"), options, len(options), handleMenuPress)
+}
+
+func (gui *Gui) handleCreateDefaultPullModeMenu(g *gocui.Gui, v *gocui.View) error {
+	options := append(gui.pullModeOptions(), &pullModeOption{
+		description: gui.Tr.SLocalize("pullModeAsk"),
+	})
+
+	handleMenuPress := func(index int) error {
+		gui.getRepoState().PullMode = options[index].mode
+		return gui.Config.SaveAppState()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("DefaultPullModeTitle"), options, len(options), handleMenuPress)
+}
+
+// pushWithOpts pushes the checked out branch, showing the output of the push,
+// and of any pre-push hook, as it comes in
+func (gui *Gui) pushWithOpts(g *gocui.Gui, v *gocui.View, opts commands.PushOpts) error {
+	unamePassOpened := false
+	branchName := gui.State.Branches[0].Name
+	run := func(git *commands.GitCommand, stream io.Writer) error {
+		err := git.Push(branchName, opts, func(passOrUname string) string {
+			unamePassOpened = true
 			return gui.waitForPassUname(g, v, passOrUname)
-		})
-		gui.HandleCredentialsPopup(g, unamePassOpend, err)
-	}()
-	return nil
+		}, stream)
+		if unamePassOpened {
+			_, _ = g.SetViewOnBottom("credentials")
+		}
+		if err != nil && strings.Contains(err.Error(), "Invalid username or password") {
+			_, _ = io.WriteString(stream, "\n"+gui.Tr.SLocalize("PassUnameWrong"))
+		}
+		return err
+	}
+	onSuccess := func() error {
+		return gui.refreshSidePanels(g)
+	}
+	var retryNoVerify func() error
+	if !opts.NoVerify {
+		retryNoVerify = func() error {
+			opts.NoVerify = true
+			return gui.pushWithOpts(g, v, opts)
+		}
+	}
+	return gui.runWithHookOutput(v, gui.Tr.SLocalize("PushWait"), run, onSuccess, retryNoVerify)
 }
 
 func (gui *Gui) pushFiles(g *gocui.Gui, v *gocui.View) error {
+	return gui.pushToUpstream(g, v, commands.PushOpts{})
+}
+
+// pushToUpstream pushes the checked out branch to its upstream, asking the user
+// for a remote and remote branch to use as the upstream if it has none
+func (gui *Gui) pushToUpstream(g *gocui.Gui, v *gocui.View, opts commands.PushOpts) error {
+	branchName := gui.State.Branches[0].Name
+	remote, remoteBranch, err := gui.GitCommand.GetUpstream(branchName)
+	if err != nil {
+		return gui.promptForRemoteBranch(g, v, "", branchName, func(remote string, remoteBranch string) error {
+			opts.Remote = remote
+			opts.RemoteBranch = remoteBranch
+			opts.SetUpstream = true
+			return gui.pushWithOpts(g, v, opts)
+		})
+	}
+	opts.Remote = remote
+	opts.RemoteBranch = remoteBranch
+
 	// if we have pullables we'll ask if the user wants to force push
 	_, pullables := gui.GitCommand.GetCurrentBranchUpstreamDifferenceCount()
-	if pullables == "?" || pullables == "0" {
-		return gui.pushWithForceFlag(g, v, false)
+	if opts.Force || pullables == "?" || pullables == "0" {
+		return gui.pushWithOpts(g, v, opts)
 	}
-	err := gui.createConfirmationPanel(g, nil, gui.Tr.SLocalize("ForcePush"), gui.Tr.SLocalize("ForcePushPrompt"), func(g *gocui.Gui, v *gocui.View) error {
-		return gui.pushWithForceFlag(g, v, true)
+	err = gui.createConfirmationPanel(g, nil, gui.Tr.SLocalize("ForcePush"), gui.Tr.SLocalize("ForcePushPrompt"), func(g *gocui.Gui, v *gocui.View) error {
+		opts.Force = true
+		return gui.pushWithOpts(g, v, opts)
 	}, nil)
 	return err
 }
 
+// promptForRemoteBranch asks the user for a remote, offering the configured
+// remotes as suggestions, and then for a branch on that remote
+func (gui *Gui) promptForRemoteBranch(g *gocui.Gui, v *gocui.View, defaultRemote string, defaultBranch string, handleConfirm func(remote string, remoteBranch string) error) error {
+	remotes, err := gui.GitCommand.GetRemotes()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if len(remotes) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoRemotes"))
+	}
+	if defaultRemote == "" && len(remotes) == 1 {
+		defaultRemote = remotes[0]
+	}
+
+	return gui.createSuggestionsPromptPanel(g, v, gui.Tr.SLocalize("EnterRemote"), defaultRemote, remotes, func(g *gocui.Gui, promptView *gocui.View) error {
+		remote := gui.trimmedContent(promptView)
+		if remote == "" {
+			return nil
+		}
+		// the remote prompt is closed once we return, so we open the next one
+		// on the following tick
+		g.Update(func(g *gocui.Gui) error {
+			return gui.createPromptPanel(g, v, gui.Tr.SLocalize("EnterRemoteBranch"), defaultBranch, func(g *gocui.Gui, promptView *gocui.View) error {
+				remoteBranch := gui.trimmedContent(promptView)
+				if remoteBranch == "" {
+					return nil
+				}
+				return handleConfirm(remote, remoteBranch)
+			})
+		})
+		return nil
+	})
+}
+
+type pushOption struct {
+	description string
+	command     string
+	handler     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *pushOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description, color.New(color.FgYellow).Sprint(o.command)}
+}
+
+func (gui *Gui) handleCreatePushMenu(g *gocui.Gui, v *gocui.View) error {
+	branchName := gui.State.Branches[0].Name
+	upstreamRemote, upstreamBranch, _ := gui.GitCommand.GetUpstream(branchName)
+
+	options := []*pushOption{
+		{
+			description: gui.Tr.SLocalize("pushToUpstream"),
+			command:     "git push",
+			handler: func() error {
+				return gui.pushToUpstream(g, v, commands.PushOpts{})
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("forcePushToUpstream"),
+			command:     "git push --force-with-lease",
+			handler: func() error {
+				return gui.pushToUpstream(g, v, commands.PushOpts{Force: true})
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("pushWithoutHooks"),
+			command:     "git push --no-verify",
+			handler: func() error {
+				return gui.pushToUpstream(g, v, commands.PushOpts{NoVerify: true})
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("pushWithTags"),
+			command:     "git push --follow-tags",
+			handler: func() error {
+				return gui.pushToUpstream(g, v, commands.PushOpts{Tags: true})
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("pushToOtherBranch"),
+			command:     fmt.Sprintf("git push <remote> %s:<branch>", branchName),
+			handler: func() error {
+				return gui.promptForRemoteBranch(g, v, upstreamRemote, upstreamBranch, func(remote string, remoteBranch string) error {
+					return gui.pushWithOpts(g, v, commands.PushOpts{Remote: remote, RemoteBranch: remoteBranch})
+				})
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("setUpstreamAndPush"),
+			command:     fmt.Sprintf("git push -u <remote> %s:<branch>", branchName),
+			handler: func() error {
+				return gui.promptForRemoteBranch(g, v, upstreamRemote, branchName, func(remote string, remoteBranch string) error {
+					return gui.pushWithOpts(g, v, commands.PushOpts{Remote: remote, RemoteBranch: remoteBranch, SetUpstream: true})
+				})
+			},
+		},
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].handler()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("PushOptionsTitle"), options, len(options), handleMenuPress)
+}
+
 func (gui *Gui) handleSwitchToMerge(g *gocui.Gui, v *gocui.View) error {
 	file, err := gui.getSelectedFile(g)
 	if err != nil {
@@ -621,7 +989,7 @@ func (gui *Gui) handleCreateResetMenu(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) handleCustomCommand(g *gocui.Gui, v *gocui.View) error {
-	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("CustomCommand"), func(g *gocui.Gui, v *gocui.View) error {
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("CustomCommand"), "", func(g *gocui.Gui, v *gocui.View) error {
 		command := gui.trimmedContent(v)
 		gui.SubProcess = gui.OSCommand.RunCustomCommand(command)
 		return gui.Errors.ErrSubProcess
@@ -0,0 +1,234 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/theme"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// listFilter narrows a list panel or menu down to the items that fuzzy match
+// a query. The panel's SelectedLine still refers to the full list, so that
+// everything that acts on the selected item keeps working; Indices maps the
+// lines on screen to the items they show
+type listFilter struct {
+	Query   string
+	Indices []int
+	// Editing is true while the query is being typed into the filter panel
+	Editing bool
+}
+
+// filterTarget is the view whose filter is being typed, if any
+func (gui *Gui) filterTarget() string {
+	for viewName, filter := range gui.State.Filters {
+		if filter.Editing {
+			return viewName
+		}
+	}
+	return ""
+}
+
+func (gui *Gui) handleOpenFilter(g *gocui.Gui, v *gocui.View) error {
+	if gui.selectedLinePointer(v.Name()) == nil {
+		return nil
+	}
+
+	filter, ok := gui.State.Filters[v.Name()]
+	if !ok {
+		filter = &listFilter{}
+		gui.State.Filters[v.Name()] = filter
+	}
+	filter.Editing = true
+
+	x0, y0, x1, y1 := gui.getFilterPanelDimensions(v)
+	filterView, err := g.SetView("filter", x0, y0, x1, y1, 0)
+	if err != nil && err.Error() != "unknown view" {
+		return err
+	}
+	filterView.Title = gui.Tr.SLocalize("FilterTitle")
+	filterView.FgColor = theme.GocuiDefaultTextColor
+	filterView.Editable = true
+	filterView.Editor = gui.filterEditor()
+	filterView.Clear()
+	fmt.Fprint(filterView, filter.Query)
+	_ = filterView.SetCursor(len([]rune(filter.Query)), 0)
+
+	if _, err := g.SetViewOnTop("filter"); err != nil {
+		return err
+	}
+	return gui.switchFocus(g, v, filterView)
+}
+
+// getFilterPanelDimensions puts the filter panel over the bottom of the view
+// being filtered
+func (gui *Gui) getFilterPanelDimensions(v *gocui.View) (int, int, int, int) {
+	x0, _, x1, y1 := v.Dimensions()
+	return x0, y1 - 2, x1, y1
+}
+
+func (gui *Gui) resizeFilterPanel(g *gocui.Gui) error {
+	v, err := g.View(gui.filterTarget())
+	if err != nil {
+		return nil
+	}
+	x0, y0, x1, y1 := gui.getFilterPanelDimensions(v)
+	_, err = g.SetView("filter", x0, y0, x1, y1, 0)
+	return err
+}
+
+// filterEditor narrows the list down as the query is typed
+func (gui *Gui) filterEditor() gocui.Editor {
+	return gocui.EditorFunc(func(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+		gocui.DefaultEditor.Edit(v, key, ch, mod)
+
+		target, err := gui.g.View(gui.filterTarget())
+		if err != nil {
+			return
+		}
+		gui.State.Filters[target.Name()].Query = strings.TrimSpace(v.Buffer())
+		gui.refreshFilteredList(target)
+	})
+}
+
+// handleFilterConfirm keeps the filter and goes back to the list, or drops it
+// if it's empty or nothing matches
+func (gui *Gui) handleFilterConfirm(g *gocui.Gui, v *gocui.View) error {
+	target, err := g.View(gui.filterTarget())
+	if err != nil {
+		return nil
+	}
+	filter := gui.State.Filters[target.Name()]
+	filter.Editing = false
+	if filter.Query == "" || len(filter.Indices) == 0 {
+		gui.removeFilter(target)
+	}
+	return gui.closeFilterPanel(target)
+}
+
+// handleFilterCancel drops the filter and goes back to the full list
+func (gui *Gui) handleFilterCancel(g *gocui.Gui, v *gocui.View) error {
+	target, err := g.View(gui.filterTarget())
+	if err != nil {
+		return nil
+	}
+	gui.removeFilter(target)
+	return gui.closeFilterPanel(target)
+}
+
+func (gui *Gui) closeFilterPanel(target *gocui.View) error {
+	if err := gui.g.DeleteView("filter"); err != nil {
+		return err
+	}
+	// we don't pass the filter panel as the old view, so that a menu still
+	// returns focus to the panel it was opened from once it's done
+	return gui.switchFocus(gui.g, nil, target)
+}
+
+func (gui *Gui) removeFilter(v *gocui.View) {
+	delete(gui.State.Filters, v.Name())
+	v.Subtitle = ""
+	gui.refreshFilteredList(v)
+}
+
+// refreshFilteredList shows a list again after its filter has changed,
+// keeping the selected item in view
+func (gui *Gui) refreshFilteredList(v *gocui.View) {
+	gui.applyFilter(v)
+	gui.redrawList(v)
+	if selectedLine := gui.selectedLinePointer(v.Name()); selectedLine != nil {
+		_ = gui.focusPoint(0, *selectedLine, len(strings.Split(gui.State.RenderedLists[v.Name()], "\n")), v)
+	}
+}
+
+// applyFilter works out which items of a list match its filter. If the
+// selected item is filtered out, the first one that's left is selected
+// instead. A filter that no longer matches anything, e.g. because the files
+// it matched have been committed, is dropped rather than leaving the selected
+// item out of sight
+func (gui *Gui) applyFilter(v *gocui.View) {
+	filter, ok := gui.State.Filters[v.Name()]
+	if !ok {
+		return
+	}
+
+	lines := strings.Split(gui.State.RenderedLists[v.Name()], "\n")
+	indices := []int{}
+	for i, line := range lines {
+		if line != "" && utils.FuzzyMatch(filter.Query, utils.Decolorise(line)) {
+			indices = append(indices, i)
+		}
+	}
+	filter.Indices = indices
+
+	if len(indices) == 0 && !filter.Editing {
+		delete(gui.State.Filters, v.Name())
+		v.Subtitle = ""
+		return
+	}
+
+	v.Subtitle = ""
+	if filter.Query != "" {
+		v.Subtitle = gui.Tr.TemplateLocalize(
+			"FilterSubtitle",
+			Teml{
+				"query": filter.Query,
+				"count": len(indices),
+				"total": len(lines),
+			},
+		)
+	}
+
+	selectedLine := gui.selectedLinePointer(v.Name())
+	if selectedLine != nil && len(indices) > 0 && filteredPosition(indices, *selectedLine) == -1 {
+		*selectedLine = indices[0]
+	}
+}
+
+// filterLines returns the lines of a list that its filter lets through
+func (gui *Gui) filterLines(viewName string, lines []string) []string {
+	filter, ok := gui.State.Filters[viewName]
+	if !ok {
+		return lines
+	}
+	filtered := make([]string, 0, len(filter.Indices))
+	for _, index := range filter.Indices {
+		if index < len(lines) {
+			filtered = append(filtered, lines[index])
+		}
+	}
+	return filtered
+}
+
+// filteredIndex returns the index of the item shown on the given line of a
+// list, or -1 if there's no item there
+func (gui *Gui) filteredIndex(viewName string, line int) int {
+	filter, ok := gui.State.Filters[viewName]
+	if !ok {
+		return line
+	}
+	if line < 0 || line >= len(filter.Indices) {
+		return -1
+	}
+	return filter.Indices[line]
+}
+
+// filteredLine returns the line of a list that the given item is on, and how
+// many lines the list has, given how many items it has in full
+func (gui *Gui) filteredLine(viewName string, index int, total int) (int, int) {
+	filter, ok := gui.State.Filters[viewName]
+	if !ok {
+		return index, total
+	}
+	return filteredPosition(filter.Indices, index), len(filter.Indices)
+}
+
+func filteredPosition(indices []int, index int) int {
+	for i, filteredIndex := range indices {
+		if filteredIndex == index {
+			return i
+		}
+	}
+	return -1
+}
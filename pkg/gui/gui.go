@@ -77,6 +77,13 @@ type Gui struct {
 	statusManager *statusManager
 	credentials   credentials
 	waitForIntro  sync.WaitGroup
+	// backgroundLoads counts the loads started by loadInBackground, keyed by
+	// what's being loaded, so that only the latest one of each is applied
+	backgroundLoads      map[string]int
+	backgroundLoadsMutex sync.Mutex
+	// backgroundLoadKeys holds the key of the last load that
+	// loadCachedInBackground applied, keyed by what was loaded
+	backgroundLoadKeys map[string]string
 }
 
 // for now the staging panel state, unlike the other panel states, is going to be
@@ -87,6 +94,11 @@ type stagingPanelState struct {
 	StageableLines []int
 	HunkStarts     []int
 	Diff           string
+	ColorDiff      string
+	// SelectingRange is true once the mouse has been dragged over the diff,
+	// selecting the stageable lines from RangeStart to SelectedLine
+	SelectingRange bool
+	RangeStart     int
 }
 
 type mergingPanelState struct {
@@ -121,15 +133,42 @@ type commitFilesPanelState struct {
 	SelectedLine int
 }
 
+type commitMessagePanelState struct {
+	// Amend tells us to amend HEAD rather than create a new commit when the
+	// message is confirmed
+	Amend bool
+	// HistoryIndex is the position in the repo's commit message history that
+	// is currently shown, or -1 if we're showing the user's own message, in
+	// which case Draft holds that message while the user cycles through history
+	HistoryIndex int
+	Draft        string
+	// SkipHooks tells us to commit with --no-verify
+	SkipHooks bool
+	// draftSaveTimer goes off when it's time to save what's been typed as
+	// the draft
+	draftSaveTimer *time.Timer
+}
+
+type hookOutputPanelState struct {
+	// Running tells us the command whose output is shown is still going
+	Running bool
+	// Retry runs the command again with --no-verify after it's failed. It's
+	// nil when there's nothing to retry e.g. because hooks were already skipped
+	Retry func() error
+}
+
 type panelStates struct {
-	Files       *filePanelState
-	Branches    *branchPanelState
-	Commits     *commitPanelState
-	Stash       *stashPanelState
-	Menu        *menuPanelState
-	Staging     *stagingPanelState
-	Merging     *mergingPanelState
-	CommitFiles *commitFilesPanelState
+	Files         *filePanelState
+	Branches      *branchPanelState
+	Commits       *commitPanelState
+	Stash         *stashPanelState
+	Menu          *menuPanelState
+	Staging       *stagingPanelState
+	Merging       *mergingPanelState
+	CommitFiles   *commitFilesPanelState
+	CommitMessage *commitMessagePanelState
+	HookOutput    *hookOutputPanelState
+	CommandLog    *commandLogPanelState
 }
 
 type guiState struct {
@@ -147,6 +186,17 @@ type guiState struct {
 	WorkingTreeState    string // one of "merging", "rebasing", "normal"
 	Contexts            map[string]string
 	CherryPickedCommits []*commands.Commit
+	RenderedLists       map[string]string // keyed by view name
+	SideBySideDiff      bool
+	MainDiff            string // the diff in the main view, kept so we can lay it out again when toggling side-by-side or resizing
+	MainDiffWidth       int
+	MainLines           []string               // the main view's content, only some of which is written to the view at a time
+	ViewWindows         map[string]*viewWindow // keyed by view name
+	Search              *searchState
+	Filters             map[string]*listFilter // keyed by view name
+	ScreenMode          screenMode
+	CommandLog          []*commandLogItem
+	ShowCommandLog      bool
 }
 
 // NewGui builds a new gui handler
@@ -159,14 +209,20 @@ func NewGui(log *logrus.Entry, gitCommand *commands.GitCommand, oSCommand *comma
 		CherryPickedCommits: make([]*commands.Commit, 0),
 		StashEntries:        make([]*commands.StashEntry, 0),
 		DiffEntries:         make([]*commands.Commit, 0),
+		RenderedLists:       map[string]string{},
+		ViewWindows:         map[string]*viewWindow{},
+		Filters:             map[string]*listFilter{},
 		Platform:            *oSCommand.Platform,
 		Panels: &panelStates{
-			Files:       &filePanelState{SelectedLine: -1},
-			Branches:    &branchPanelState{SelectedLine: 0},
-			Commits:     &commitPanelState{SelectedLine: -1},
-			CommitFiles: &commitFilesPanelState{SelectedLine: -1},
-			Stash:       &stashPanelState{SelectedLine: -1},
-			Menu:        &menuPanelState{SelectedLine: 0},
+			Files:         &filePanelState{SelectedLine: -1},
+			Branches:      &branchPanelState{SelectedLine: 0},
+			Commits:       &commitPanelState{SelectedLine: -1},
+			CommitFiles:   &commitFilesPanelState{SelectedLine: -1},
+			Stash:         &stashPanelState{SelectedLine: -1},
+			Menu:          &menuPanelState{SelectedLine: 0},
+			CommitMessage: &commitMessagePanelState{HistoryIndex: -1},
+			HookOutput:    &hookOutputPanelState{},
+			CommandLog:    &commandLogPanelState{SelectedLine: 0},
 			Merging: &mergingPanelState{
 				ConflictIndex: 0,
 				ConflictTop:   true,
@@ -176,6 +232,8 @@ func NewGui(log *logrus.Entry, gitCommand *commands.GitCommand, oSCommand *comma
 		},
 	}
 
+	initialState.ShowCommandLog = config.GetUserConfig().GetBool("gui.showCommandLog")
+
 	gui := &Gui{
 		Log:           log,
 		GitCommand:    gitCommand,
@@ -185,6 +243,9 @@ func NewGui(log *logrus.Entry, gitCommand *commands.GitCommand, oSCommand *comma
 		Tr:            tr,
 		Updater:       updater,
 		statusManager: &statusManager{},
+
+		backgroundLoads:    map[string]int{},
+		backgroundLoadKeys: map[string]string{},
 	}
 
 	gui.GenerateSentinelErrors()
@@ -196,7 +257,11 @@ func (gui *Gui) scrollUpMain(g *gocui.Gui, v *gocui.View) error {
 	mainView, _ := g.View("main")
 	ox, oy := mainView.Origin()
 	newOy := int(math.Max(0, float64(oy-gui.Config.GetUserConfig().GetInt("gui.scrollHeight"))))
-	return mainView.SetOrigin(ox, newOy)
+	if err := mainView.SetOrigin(ox, newOy); err != nil {
+		return err
+	}
+	gui.refreshMainWindow(mainView)
+	return nil
 }
 
 func (gui *Gui) scrollDownMain(g *gocui.Gui, v *gocui.View) error {
@@ -207,8 +272,12 @@ func (gui *Gui) scrollDownMain(g *gocui.Gui, v *gocui.View) error {
 		_, sy := mainView.Size()
 		y += sy
 	}
-	if y < len(mainView.BufferLines()) {
-		return mainView.SetOrigin(ox, oy+gui.Config.GetUserConfig().GetInt("gui.scrollHeight"))
+	// there's more below if we've only written some of the content
+	if gui.viewWindow("main").End < len(gui.State.MainLines) || y < len(mainView.BufferLines()) {
+		if err := mainView.SetOrigin(ox, oy+gui.Config.GetUserConfig().GetInt("gui.scrollHeight")); err != nil {
+			return err
+		}
+		gui.refreshMainWindow(mainView)
 	}
 	return nil
 }
@@ -217,13 +286,6 @@ func (gui *Gui) handleRefresh(g *gocui.Gui, v *gocui.View) error {
 	return gui.refreshSidePanels(g)
 }
 
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
-
 // getFocusLayout returns a manager function for when view gain and lose focus
 func (gui *Gui) getFocusLayout() func(g *gocui.Gui) error {
 	var previousView *gocui.View
@@ -250,6 +312,7 @@ func (gui *Gui) onFocusChange() error {
 	currentView := gui.g.CurrentView()
 	for _, view := range gui.g.Views() {
 		view.Highlight = view == currentView
+		gui.renderSelectedLine(view)
 	}
 	return gui.setMainTitle()
 }
@@ -258,6 +321,11 @@ func (gui *Gui) onFocusLost(v *gocui.View, newView *gocui.View) error {
 	if v == nil {
 		return nil
 	}
+	if gui.State.Search != nil && v.Name() == gui.State.Search.KeyView {
+		if err := gui.clearSearch(); err != nil {
+			return err
+		}
+	}
 	if v.Name() == "branches" {
 		// This stops the branches panel from showing the upstream/downstream changes to the selected branch, when it loses focus
 		// inside renderListPanel it checks to see if the panel has focus
@@ -330,36 +398,17 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 		}
 	}
 
-	usableSpace := height - 7
-	extraSpace := usableSpace - (usableSpace/3)*3
-
-	vHeights := map[string]int{
-		"status":   3,
-		"files":    (usableSpace / 3) + extraSpace,
-		"branches": usableSpace / 3,
-		"commits":  usableSpace / 3,
-		"stash":    3,
-		"options":  1,
-	}
-
-	if height < 28 {
-		defaultHeight := 3
-		if height < 21 {
-			defaultHeight = 1
-		}
-		vHeights = map[string]int{
-			"status":   defaultHeight,
-			"files":    defaultHeight,
-			"branches": defaultHeight,
-			"commits":  defaultHeight,
-			"stash":    defaultHeight,
-			"options":  defaultHeight,
+	mainFocused := false
+	if currView != nil {
+		focusedView := currView.Name()
+		if gui.isPopupPanel(focusedView) {
+			focusedView = gui.State.PreviousView
 		}
-		vHeights[currentCyclebleView] = height - defaultHeight*4 - 1
+		mainFocused = focusedView == "main" || focusedView == "commandLog"
 	}
+	viewDimensions := gui.getViewDimensions(width, height, currentCyclebleView, mainFocused)
 
-	optionsVersionBoundary := width - max(len(utils.Decolorise(information)), 1)
-	leftSideWidth := width / 3
+	optionsVersionBoundary := width - utils.Max(len(utils.Decolorise(information)), 1)
 
 	appStatus := gui.statusManager.getStatusString()
 	appStatusOptionsBoundary := 0
@@ -367,16 +416,16 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 		appStatusOptionsBoundary = len(appStatus) + 2
 	}
 
-	panelSpacing := 1
-	if OverlappingEdges {
-		panelSpacing = 0
-	}
-
 	_, _ = g.SetViewOnBottom("limit")
 	g.DeleteView("limit")
 
 	textColor := theme.GocuiDefaultTextColor
-	v, err := g.SetView("main", leftSideWidth+panelSpacing, 0, width-1, height-2, gocui.LEFT)
+	mainOverlaps := byte(gocui.LEFT)
+	if gui.isPortrait(width, height) {
+		mainOverlaps = gocui.TOP
+	}
+	main := viewDimensions["main"]
+	v, err := g.SetView("main", main.x0, main.y0, main.x1, main.y1, mainOverlaps)
 	if err != nil {
 		if err.Error() != "unknown view" {
 			return err
@@ -385,8 +434,30 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 		v.Wrap = true
 		v.FgColor = textColor
 	}
+	mainWidth, _ := v.Size()
+	gui.GitCommand.SetPagerWidth(mainWidth)
+	if gui.State.SideBySideDiff && gui.State.MainDiff != "" && gui.State.MainDiffWidth != mainWidth {
+		if err := gui.setDiffContent(v, gui.State.MainDiff); err != nil {
+			return err
+		}
+	}
+	// the view may have grown past what we've written to it
+	gui.refreshMainWindow(v)
 
-	if v, err := g.SetView("status", 0, 0, leftSideWidth, vHeights["status"]-1, gocui.BOTTOM|gocui.RIGHT); err != nil {
+	commandLogView, err := gui.setViewFromDimensions("commandLog", viewDimensions)
+	if err != nil {
+		if err.Error() != "unknown view" {
+			return err
+		}
+		commandLogView.Title = gui.Tr.SLocalize("CommandLogTitle")
+		commandLogView.FgColor = textColor
+		if err := gui.renderCommandLog(); err != nil {
+			return err
+		}
+	}
+
+	status := viewDimensions["status"]
+	if v, err := g.SetView("status", status.x0, status.y0, status.x1, status.y1, gocui.BOTTOM|gocui.RIGHT); err != nil {
 		if err.Error() != "unknown view" {
 			return err
 		}
@@ -394,7 +465,7 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 		v.FgColor = textColor
 	}
 
-	filesView, err := g.SetViewBeneath("files", "status", vHeights["files"])
+	filesView, err := gui.setViewFromDimensions("files", viewDimensions)
 	if err != nil {
 		if err.Error() != "unknown view" {
 			return err
@@ -404,7 +475,7 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 		v.FgColor = textColor
 	}
 
-	branchesView, err := g.SetViewBeneath("branches", "files", vHeights["branches"])
+	branchesView, err := gui.setViewFromDimensions("branches", viewDimensions)
 	if err != nil {
 		if err.Error() != "unknown view" {
 			return err
@@ -413,7 +484,7 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 		branchesView.FgColor = textColor
 	}
 
-	if v, err := g.SetViewBeneath("commitFiles", "branches", vHeights["commits"]); err != nil {
+	if v, err := gui.setViewFromDimensions("commitFiles", viewDimensions); err != nil {
 		if err.Error() != "unknown view" {
 			return err
 		}
@@ -421,7 +492,7 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 		v.FgColor = textColor
 	}
 
-	commitsView, err := g.SetViewBeneath("commits", "branches", vHeights["commits"])
+	commitsView, err := gui.setViewFromDimensions("commits", viewDimensions)
 	if err != nil {
 		if err.Error() != "unknown view" {
 			return err
@@ -430,7 +501,7 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 		commitsView.FgColor = textColor
 	}
 
-	stashView, err := g.SetViewBeneath("stash", "commits", vHeights["stash"])
+	stashView, err := gui.setViewFromDimensions("stash", viewDimensions)
 	if err != nil {
 		if err.Error() != "unknown view" {
 			return err
@@ -444,8 +515,7 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 			return err
 		}
 		v.Frame = false
-		userConfig := gui.Config.GetUserConfig()
-		v.FgColor = theme.GetColor(userConfig.GetStringSlice("gui.theme.optionsTextColor"))
+		v.FgColor = theme.OptionsTextColor
 	}
 
 	if gui.getCommitMessageView() == nil {
@@ -458,6 +528,21 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 			commitMessageView.Title = gui.Tr.SLocalize("CommitMessage")
 			commitMessageView.FgColor = textColor
 			commitMessageView.Editable = true
+			commitMessageView.Editor = gui.commitMessageEditor()
+		}
+	}
+
+	if gui.getCommitDescriptionView() == nil {
+		// doesn't matter where this view starts because it will be hidden
+		if commitDescriptionView, err := g.SetView("commitDescription", width, height, width*2, height*2, 0); err != nil {
+			if err.Error() != "unknown view" {
+				return err
+			}
+			g.SetViewOnBottom("commitDescription")
+			commitDescriptionView.Title = gui.Tr.SLocalize("CommitDescription")
+			commitDescriptionView.FgColor = textColor
+			commitDescriptionView.Editable = true
+			commitDescriptionView.Editor = gui.commitMessageEditor()
 		}
 	}
 
@@ -547,6 +632,10 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 }
 
 func (gui *Gui) loadNewRepo() error {
+	if err := gui.Config.LoadRepoConfig(gui.GitCommand.DotGitDir); err != nil {
+		return err
+	}
+	gui.GitCommand.DiffOptions = gui.getRepoState().DiffOptions
 	gui.Updater.CheckForNewUpdate(gui.onBackgroundUpdateCheckFinish, false)
 	if err := gui.updateRecentRepoList(); err != nil {
 		return err
@@ -575,9 +664,9 @@ func (gui *Gui) promptAnonymousReporting() error {
 	})
 }
 
-func (gui *Gui) fetch(g *gocui.Gui, v *gocui.View, canAskForCredentials bool) (unamePassOpend bool, err error) {
+func (gui *Gui) fetch(g *gocui.Gui, v *gocui.View, git *commands.GitCommand, canAskForCredentials bool) (unamePassOpend bool, err error) {
 	unamePassOpend = false
-	err = gui.GitCommand.Fetch(func(passOrUname string) string {
+	err = git.Fetch(func(passOrUname string) string {
 		unamePassOpend = true
 		return gui.waitForPassUname(gui.g, v, passOrUname)
 	}, canAskForCredentials)
@@ -605,11 +694,11 @@ func (gui *Gui) renderAppStatus() error {
 
 func (gui *Gui) renderGlobalOptions() error {
 	return gui.renderOptionsMap(map[string]string{
-		"PgUp/PgDn": gui.Tr.SLocalize("scroll"),
-		"← → ↑ ↓":   gui.Tr.SLocalize("navigate"),
-		"esc/q":     gui.Tr.SLocalize("close"),
-		"x":         gui.Tr.SLocalize("menu"),
-		"1-5":       gui.Tr.SLocalize("jump"),
+		fmt.Sprintf("%s/%s", gui.getKeyDisplay("universal.scrollUpMain"), gui.getKeyDisplay("universal.scrollDownMain")):                                                                                 gui.Tr.SLocalize("scroll"),
+		fmt.Sprintf("%s %s %s %s", gui.getKeyDisplay("universal.prevBlock"), gui.getKeyDisplay("universal.nextBlock"), gui.getKeyDisplay("universal.prevItem"), gui.getKeyDisplay("universal.nextItem")): gui.Tr.SLocalize("navigate"),
+		fmt.Sprintf("%s/%s", gui.getKeyDisplay("universal.return"), gui.getKeyDisplay("universal.quit")):                                                                                                 gui.Tr.SLocalize("close"),
+		gui.getKeyDisplay("universal.optionMenu"): gui.Tr.SLocalize("menu"),
+		fmt.Sprintf("%s-%s", gui.getKeyDisplay("universal.goToStatus"), gui.getKeyDisplay("universal.goToStash")): gui.Tr.SLocalize("jump"),
 	})
 }
 
@@ -621,18 +710,102 @@ func (gui *Gui) goEvery(interval time.Duration, function func() error) {
 	}()
 }
 
+// loadInBackground runs load on a background worker, so that slow git
+// commands don't hold up rendering, and then applies what it returns in the
+// gui's own goroutine. If the same thing is loaded again in the meantime, only
+// the latest load is applied. A load that fails is only logged, as there's
+// nothing the user can do about it and it'll be loaded again soon enough
+func (gui *Gui) loadInBackground(name string, load func() (func(*gocui.Gui) error, error)) {
+	loadNumber := gui.startBackgroundLoad(name)
+
+	go func() {
+		apply, err := load()
+		gui.g.Update(func(g *gocui.Gui) error {
+			if !gui.isLatestBackgroundLoad(name, loadNumber) {
+				return nil
+			}
+			if err != nil {
+				gui.Log.Error(err)
+				return nil
+			}
+			return apply(g)
+		})
+	}()
+}
+
+// startBackgroundLoad counts a load that's starting, returning its number for
+// isLatestBackgroundLoad to check against
+func (gui *Gui) startBackgroundLoad(name string) int {
+	gui.backgroundLoadsMutex.Lock()
+	defer gui.backgroundLoadsMutex.Unlock()
+	gui.backgroundLoads[name]++
+	return gui.backgroundLoads[name]
+}
+
+// isLatestBackgroundLoad tells us whether a load is still the latest of what
+// it's loading, and hasn't been dropped
+func (gui *Gui) isLatestBackgroundLoad(name string, loadNumber int) bool {
+	gui.backgroundLoadsMutex.Lock()
+	defer gui.backgroundLoadsMutex.Unlock()
+	return gui.backgroundLoads[name] == loadNumber
+}
+
+// loadCachedInBackground is loadInBackground for things that only change when
+// what key returns does, e.g. the branches, which only change with the refs.
+// key is called on the background worker before loading, and if it returns the
+// same as it did for the last load that was applied, there's nothing to load.
+// An empty key means we can't tell, so we load anyway
+func (gui *Gui) loadCachedInBackground(name string, key func() string, load func() (func(*gocui.Gui) error, error)) {
+	gui.loadInBackground(name, func() (func(*gocui.Gui) error, error) {
+		loadKey := key()
+		gui.backgroundLoadsMutex.Lock()
+		unchanged := loadKey != "" && gui.backgroundLoadKeys[name] == loadKey
+		gui.backgroundLoadsMutex.Unlock()
+		if unchanged {
+			return func(*gocui.Gui) error { return nil }, nil
+		}
+
+		apply, err := load()
+		if err != nil {
+			return nil, err
+		}
+		return func(g *gocui.Gui) error {
+			gui.backgroundLoadsMutex.Lock()
+			gui.backgroundLoadKeys[name] = loadKey
+			gui.backgroundLoadsMutex.Unlock()
+			return apply(g)
+		}, nil
+	})
+}
+
+// dropBackgroundLoads stops the loads of something that are under way from
+// being applied, e.g. because we've just loaded it again ourselves
+func (gui *Gui) dropBackgroundLoads(name string) {
+	gui.backgroundLoadsMutex.Lock()
+	defer gui.backgroundLoadsMutex.Unlock()
+	gui.backgroundLoads[name]++
+}
+
+// forgetBackgroundLoadKeys makes the next cached loads load regardless, e.g.
+// because the views they render to have been recreated
+func (gui *Gui) forgetBackgroundLoadKeys() {
+	gui.backgroundLoadsMutex.Lock()
+	defer gui.backgroundLoadsMutex.Unlock()
+	gui.backgroundLoadKeys = map[string]string{}
+}
+
 func (gui *Gui) startBackgroundFetch() {
 	gui.waitForIntro.Wait()
 	isNew := gui.Config.GetIsNewRepo()
 	if !isNew {
 		time.After(60 * time.Second)
 	}
-	_, err := gui.fetch(gui.g, gui.g.CurrentView(), false)
+	_, err := gui.fetch(gui.g, gui.g.CurrentView(), gui.GitCommand, false)
 	if err != nil && strings.Contains(err.Error(), "exit status 128") && isNew {
 		_ = gui.createConfirmationPanel(gui.g, gui.g.CurrentView(), gui.Tr.SLocalize("NoAutomaticGitFetchTitle"), gui.Tr.SLocalize("NoAutomaticGitFetchBody"), nil, nil)
 	} else {
 		gui.goEvery(time.Second*60, func() error {
-			_, err := gui.fetch(gui.g, gui.g.CurrentView(), false)
+			_, err := gui.fetch(gui.g, gui.g.CurrentView(), gui.GitCommand, false)
 			return err
 		})
 	}
@@ -640,7 +813,14 @@ func (gui *Gui) startBackgroundFetch() {
 
 // Run setup the gui with keybindings and start the mainloop
 func (gui *Gui) Run() error {
-	g, err := gocui.NewGui(gocui.OutputNormal, OverlappingEdges)
+	// we need to know up front whether the theme uses the 256 color palette
+	theme.UpdateTheme(gui.Config.GetUserConfig())
+	outputMode := gocui.OutputNormal
+	if theme.Uses256Colors {
+		outputMode = gocui.Output256
+	}
+
+	g, err := gocui.NewGui(outputMode, OverlappingEdges)
 	if err != nil {
 		return err
 	}
@@ -651,6 +831,8 @@ func (gui *Gui) Run() error {
 	}
 
 	gui.g = g // TODO: always use gui.g rather than passing g around everywhere
+	gui.forgetBackgroundLoadKeys()
+	gui.OSCommand.SetCommandLogger(gui.onCommandLogged)
 
 	if err := gui.setColorScheme(); err != nil {
 		return err
@@ -665,7 +847,8 @@ func (gui *Gui) Run() error {
 	if gui.Config.GetUserConfig().GetBool("git.autoFetch") {
 		go gui.startBackgroundFetch()
 	}
-	gui.goEvery(time.Second*10, gui.refreshFiles)
+	stopFileWatcher := gui.startFileWatcher()
+	defer stopFileWatcher()
 	gui.goEvery(time.Millisecond*50, gui.renderAppStatus)
 
 	g.SetManager(gocui.ManagerFunc(gui.layout), gocui.ManagerFunc(gui.getFocusLayout()))
@@ -725,6 +908,11 @@ func (gui *Gui) runCommand() error {
 }
 
 func (gui *Gui) quit(g *gocui.Gui, v *gocui.View) error {
+	if v != nil && (v.Name() == "commitMessage" || v.Name() == "commitDescription") && !gui.State.Panels.CommitMessage.Amend {
+		if err := gui.saveCommitMessageDraft(); err != nil {
+			return err
+		}
+	}
 	if gui.State.Updating {
 		return gui.createUpdateQuitConfirmation(g, v)
 	}
@@ -748,11 +936,9 @@ func (gui *Gui) handleDonate(g *gocui.Gui, v *gocui.View) error {
 	return gui.OSCommand.OpenLink("https://donorbox.org/lazygit")
 }
 
-// setColorScheme sets the color scheme for the app based on the user config
+// setColorScheme sets the color scheme for the app based on the theme, which
+// has been loaded from the user config by now
 func (gui *Gui) setColorScheme() error {
-	userConfig := gui.Config.GetUserConfig()
-	theme.UpdateTheme(userConfig)
-
 	gui.g.FgColor = theme.InactiveBorderColor
 	gui.g.SelFgColor = theme.ActiveBorderColor
 
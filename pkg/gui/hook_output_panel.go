@@ -0,0 +1,187 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/theme"
+)
+
+// hookOutputWriter writes whatever it receives to the hookOutput view. Writes
+// come in from the goroutine running the command, so we hand them over to the
+// gui's own goroutine
+type hookOutputWriter struct {
+	gui *Gui
+}
+
+func (w *hookOutputWriter) Write(p []byte) (int, error) {
+	// commands run on a pty end their lines with \r\n, and the view treats a
+	// carriage return as the start of a line to be overwritten
+	content := strings.Replace(string(p), "\r\n", "\n", -1)
+	w.gui.g.Update(func(g *gocui.Gui) error {
+		v, err := g.View("hookOutput")
+		if err != nil {
+			return nil // the panel has been closed in the meantime
+		}
+		fmt.Fprint(v, content)
+		return nil
+	})
+	return len(p), nil
+}
+
+func (gui *Gui) getHookOutputPanelDimensions(g *gocui.Gui) (int, int, int, int) {
+	width, height := g.Size()
+	return width / 8, height / 8, width - width/8, height - height/8
+}
+
+func (gui *Gui) resizeHookOutputPanel(g *gocui.Gui) error {
+	x0, y0, x1, y1 := gui.getHookOutputPanelDimensions(g)
+	_, err := g.SetView("hookOutput", x0, y0, x1, y1, 0)
+	return err
+}
+
+// runWithHookOutput runs a command that may trigger git hooks in the
+// background, showing its output in a panel as it comes in so that a slow hook
+// doesn't look like lazygit has hung. If the command succeeds the panel is
+// closed and onSuccess is called. Otherwise the panel stays open so that the
+// user can read through the output, and retry the command with --no-verify by
+// way of retryNoVerify if it's not nil
+func (gui *Gui) runWithHookOutput(currentView *gocui.View, title string, run func(git *commands.GitCommand, stream io.Writer) error, onSuccess func() error, retryNoVerify func() error) error {
+	gui.onNewPopupPanel()
+	x0, y0, x1, y1 := gui.getHookOutputPanelDimensions(gui.g)
+	v, err := gui.g.SetView("hookOutput", x0, y0, x1, y1, 0)
+	if err != nil && err.Error() != "unknown view" {
+		return err
+	}
+	v.Clear()
+	v.Title = title
+	v.HasLoader = true
+	v.Wrap = true
+	v.Autoscroll = true
+	v.FgColor = theme.GocuiDefaultTextColor
+	gui.State.Panels.HookOutput = &hookOutputPanelState{Running: true}
+	if err := gui.switchFocus(gui.g, currentView, v); err != nil {
+		return err
+	}
+
+	go func() {
+		cancelled, cmdErr := gui.runTask(title, func(git *commands.GitCommand) error {
+			return run(git, &hookOutputWriter{gui: gui})
+		})
+		gui.g.Update(func(g *gocui.Gui) error {
+			gui.State.Panels.HookOutput.Running = false
+			if cmdErr == nil || cancelled {
+				if err := gui.closeHookOutputPanel(g); err != nil {
+					return err
+				}
+				if cancelled {
+					return gui.onTaskCancelled()
+				}
+				return onSuccess()
+			}
+			return gui.onHookOutputFailure(g, cmdErr, retryNoVerify)
+		})
+	}()
+	return nil
+}
+
+func (gui *Gui) onHookOutputFailure(g *gocui.Gui, cmdErr error, retryNoVerify func() error) error {
+	v, err := g.View("hookOutput")
+	if err != nil {
+		return gui.createErrorPanel(g, cmdErr.Error())
+	}
+	gui.State.Panels.HookOutput.Retry = retryNoVerify
+	v.HasLoader = false
+	v.Title = gui.Tr.SLocalize("CommandFailed")
+	if strings.TrimSpace(v.Buffer()) == "" {
+		fmt.Fprint(v, cmdErr.Error())
+	}
+	return gui.renderHookOutputOptions(g)
+}
+
+func (gui *Gui) closeHookOutputPanel(g *gocui.Gui) error {
+	v, err := g.View("hookOutput")
+	if err != nil {
+		return nil // it's already been closed
+	}
+	if err := gui.returnFocus(g, v); err != nil {
+		return err
+	}
+	return g.DeleteView("hookOutput")
+}
+
+func (gui *Gui) handleHookOutputClose(g *gocui.Gui, v *gocui.View) error {
+	// closing the panel while the command is running cancels it, and the panel
+	// is closed once the command has stopped
+	if gui.State.Panels.HookOutput.Running {
+		return gui.handleCancelCommands(g, v)
+	}
+	return gui.closeHookOutputPanel(g)
+}
+
+func (gui *Gui) handleHookOutputRetry(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.HookOutput
+	if state.Running || state.Retry == nil {
+		return nil
+	}
+	if err := gui.closeHookOutputPanel(g); err != nil {
+		return err
+	}
+	return state.Retry()
+}
+
+func (gui *Gui) handleHookOutputScrollUp(g *gocui.Gui, v *gocui.View) error {
+	// we stop following new output once the user wants to look further up
+	v.Autoscroll = false
+	ox, oy := v.Origin()
+	if oy > 0 {
+		return v.SetOrigin(ox, oy-1)
+	}
+	return nil
+}
+
+func (gui *Gui) handleHookOutputScrollDown(g *gocui.Gui, v *gocui.View) error {
+	ox, oy := v.Origin()
+	_, height := v.Size()
+	if oy+height >= v.ViewLinesHeight() {
+		return nil
+	}
+	return v.SetOrigin(ox, oy+1)
+}
+
+func (gui *Gui) handleHookOutputFocused(g *gocui.Gui, v *gocui.View) error {
+	return gui.renderHookOutputOptions(g)
+}
+
+func (gui *Gui) renderHookOutputOptions(g *gocui.Gui) error {
+	state := gui.State.Panels.HookOutput
+	if state.Running {
+		return gui.renderString(g, "options", gui.Tr.TemplateLocalize(
+			"HookOutputRunningOptions",
+			Teml{
+				"keyBindCancel": gui.getKeyDisplay("universal.return"),
+				"keyBindScroll": fmt.Sprintf("%s/%s", gui.getKeyDisplay("universal.prevItem"), gui.getKeyDisplay("universal.nextItem")),
+			},
+		))
+	}
+	if state.Retry == nil {
+		return gui.renderString(g, "options", gui.Tr.TemplateLocalize(
+			"HookOutputOptions",
+			Teml{
+				"keyBindClose":  gui.getKeyDisplay("universal.return"),
+				"keyBindScroll": fmt.Sprintf("%s/%s", gui.getKeyDisplay("universal.prevItem"), gui.getKeyDisplay("universal.nextItem")),
+			},
+		))
+	}
+	return gui.renderString(g, "options", gui.Tr.TemplateLocalize(
+		"HookOutputRetryOptions",
+		Teml{
+			"keyBindClose":  gui.getKeyDisplay("universal.return"),
+			"keyBindRetry":  gui.getKeyDisplay("universal.confirm"),
+			"keyBindScroll": fmt.Sprintf("%s/%s", gui.getKeyDisplay("universal.prevItem"), gui.getKeyDisplay("universal.nextItem")),
+		},
+	))
+}
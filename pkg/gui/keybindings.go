@@ -1,14 +1,28 @@
 package gui
 
 import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/go-errors/errors"
 	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+	"github.com/jesseduffield/termbox-go"
 )
 
+// modMotion is the modifier of a mouse event for the mouse moving with its
+// button held down, which gocui doesn't have a name for
+const modMotion = gocui.Modifier(termbox.ModMotion)
+
 // Binding - a keybinding mapping a key and modifier to a handler. The keypress
 // is only handled if the given view has focus, or handled globally if the view
-// is ""
+// is "". Bindings that can be remapped in the keybinding section of the config
+// have a Name, e.g. files.commitChanges, in place of a Key. The key is filled
+// in from the config by resolveKeybindings
 type Binding struct {
 	ViewName    string
+	Name        string
 	Handler     func(*gocui.Gui, *gocui.View) error
 	Key         interface{} // FIXME: find out how to get `gocui.Key | rune`
 	Modifier    gocui.Modifier
@@ -23,473 +37,887 @@ func (b *Binding) GetDisplayStrings(isFocused bool) []string {
 
 // GetKey is a function.
 func (b *Binding) GetKey() string {
-	key := 0
+	return getKeyDisplay(b.Key)
+}
+
+// keysByLabel holds the keys that are written in the config by name rather
+// than as the character they type
+var keysByLabel = map[string]gocui.Key{
+	"<esc>":       gocui.KeyEsc,
+	"<enter>":     gocui.KeyEnter,
+	"<tab>":       gocui.KeyTab,
+	"<space>":     gocui.KeySpace,
+	"<backspace>": gocui.KeyBackspace2,
+	"<delete>":    gocui.KeyDelete,
+	"<insert>":    gocui.KeyInsert,
+	"<home>":      gocui.KeyHome,
+	"<end>":       gocui.KeyEnd,
+	"<pgup>":      gocui.KeyPgup,
+	"<pgdown>":    gocui.KeyPgdn,
+	"<up>":        gocui.KeyArrowUp,
+	"<down>":      gocui.KeyArrowDown,
+	"<left>":      gocui.KeyArrowLeft,
+	"<right>":     gocui.KeyArrowRight,
+	"<f1>":        gocui.KeyF1,
+	"<f2>":        gocui.KeyF2,
+	"<f3>":        gocui.KeyF3,
+	"<f4>":        gocui.KeyF4,
+	"<f5>":        gocui.KeyF5,
+	"<f6>":        gocui.KeyF6,
+	"<f7>":        gocui.KeyF7,
+	"<f8>":        gocui.KeyF8,
+	"<f9>":        gocui.KeyF9,
+	"<f10>":       gocui.KeyF10,
+	"<f11>":       gocui.KeyF11,
+	"<f12>":       gocui.KeyF12,
+}
+
+// keyDisplays is how we show the keys that don't show up as a character
+var keyDisplays = map[gocui.Key]string{
+	gocui.KeyEsc:        "esc",
+	gocui.KeyEnter:      "enter",
+	gocui.KeyTab:        "tab",
+	gocui.KeySpace:      "space",
+	gocui.KeyBackspace2: "backspace",
+	gocui.KeyDelete:     "del",
+	gocui.KeyInsert:     "ins",
+	gocui.KeyHome:       "home",
+	gocui.KeyEnd:        "end",
+	gocui.KeyPgup:       "PgUp",
+	gocui.KeyPgdn:       "PgDn",
+	gocui.KeyArrowUp:    "▲",
+	gocui.KeyArrowDown:  "▼",
+	gocui.KeyArrowLeft:  "◄",
+	gocui.KeyArrowRight: "►",
+	gocui.KeyF1:         "F1",
+	gocui.KeyF2:         "F2",
+	gocui.KeyF3:         "F3",
+	gocui.KeyF4:         "F4",
+	gocui.KeyF5:         "F5",
+	gocui.KeyF6:         "F6",
+	gocui.KeyF7:         "F7",
+	gocui.KeyF8:         "F8",
+	gocui.KeyF9:         "F9",
+	gocui.KeyF10:        "F10",
+	gocui.KeyF11:        "F11",
+	gocui.KeyF12:        "F12",
 
-	switch b.Key.(type) {
+	// scrolling the main panel with the mouse wheel is like paging through it
+	gocui.MouseWheelUp:   "PgUp",
+	gocui.MouseWheelDown: "PgDn",
+}
+
+func getKeyDisplay(key interface{}) string {
+	switch key := key.(type) {
 	case rune:
-		key = int(b.Key.(rune))
+		if key == ' ' {
+			return "space"
+		}
+		return string(key)
 	case gocui.Key:
-		if b.Key.(gocui.Key) == gocui.KeyCtrlJ {
-			return "ctrl+j"
+		if display, ok := keyDisplays[key]; ok {
+			return display
+		}
+		if key >= gocui.KeyCtrlA && key <= gocui.KeyCtrlZ {
+			return fmt.Sprintf("ctrl+%c", 'a'+rune(key-gocui.KeyCtrlA))
+		}
+		return string(rune(key))
+	}
+	return ""
+}
+
+// parseKey turns a key from the config into one we can bind to. A key is
+// either a single character like `a` or `A`, a control key like `<c-a>`, or
+// one of the named keys in keysByLabel like `<enter>`. `<disabled>` gives a
+// nil key, meaning the action isn't bound at all
+func parseKey(key string) (interface{}, error) {
+	if key == "<disabled>" {
+		return nil, nil
+	}
+	runes := []rune(key)
+	if len(runes) == 1 {
+		return runes[0], nil
+	}
+	if namedKey, ok := keysByLabel[strings.ToLower(key)]; ok {
+		return namedKey, nil
+	}
+	if len(runes) == 5 && strings.HasPrefix(key, "<c-") && strings.HasSuffix(key, ">") {
+		letter := unicode.ToLower(runes[3])
+		if letter >= 'a' && letter <= 'z' {
+			return gocui.KeyCtrlA + gocui.Key(letter-'a'), nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("unknown key %q", key))
+}
+
+// getKey returns the key the given action is bound to in the keybinding
+// section of the config e.g. 'c' for files.commitChanges
+func (gui *Gui) getKey(name string) (interface{}, error) {
+	key := gui.Config.GetUserConfig().GetString("keybinding." + name)
+	if key == "" {
+		return nil, errors.New(fmt.Sprintf("keybinding.%s: no key given", name))
+	}
+	parsedKey, err := parseKey(key)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("keybinding.%s: %v", name, err))
+	}
+	return parsedKey, nil
+}
+
+// getKeyDisplay returns the key the given action is bound to, as it's shown in
+// the options bar and menus
+func (gui *Gui) getKeyDisplay(name string) string {
+	key, err := gui.getKey(name)
+	if err != nil || key == nil {
+		return ""
+	}
+	return getKeyDisplay(key)
+}
+
+// setNamedKeybindings binds the keys of the given actions to the handler in a
+// view that's created on the fly, like the confirmation popup. Disabled
+// actions are skipped
+func (gui *Gui) setNamedKeybindings(viewName string, names []string, handler func(*gocui.Gui, *gocui.View) error) error {
+	for _, name := range names {
+		key, err := gui.getKey(name)
+		if err != nil || key == nil {
+			continue
 		}
-		if b.Key.(gocui.Key) == gocui.KeyCtrlK {
-			return "ctrl+k"
+		if err := gui.g.SetKeybinding(viewName, key, gocui.ModNone, handler); err != nil {
+			return err
 		}
-		key = int(b.Key.(gocui.Key))
 	}
+	return nil
+}
 
-	// special keys
-	switch key {
-	case 27:
-		return "esc"
-	case 13:
-		return "enter"
-	case 32:
-		return "space"
-	case 65514:
-		return "►"
-	case 65515:
-		return "◄"
-	case 65517:
-		return "▲"
-	case 65516:
-		return "▼"
-	case 65508:
-		return "PgUp"
-	case 65507:
-		return "PgDn"
+// deleteNamedKeybindings undoes setNamedKeybindings. It doesn't matter if the
+// keys were never bound
+func (gui *Gui) deleteNamedKeybindings(viewName string, names []string) {
+	for _, name := range names {
+		if key, err := gui.getKey(name); err == nil && key != nil {
+			_ = gui.g.DeleteKeybinding(viewName, key, gocui.ModNone)
+		}
 	}
+}
+
+// resolveKeybindings fills in the keys of the named bindings from the config.
+// Bindings whose action has been disabled, or whose key can't be understood,
+// are left out, with the latter reported by validateKeybindings on startup
+func (gui *Gui) resolveKeybindings(bindings []*Binding) []*Binding {
+	resolved := make([]*Binding, 0, len(bindings))
+	for _, binding := range bindings {
+		if binding.Name != "" {
+			key, err := gui.getKey(binding.Name)
+			if err != nil || key == nil {
+				continue
+			}
+			binding.Key = key
+		}
+		resolved = append(resolved, binding)
+	}
+	return resolved
+}
+
+// KeybindingConfigError lists the problems with the keybinding section of the
+// user's config, so that we can report them all at once on startup
+type KeybindingConfigError struct {
+	Problems []string
+}
 
-	return string(key)
+func (e *KeybindingConfigError) Error() string {
+	return "Problems with the keybinding config:\n  " + strings.Join(e.Problems, "\n  ")
 }
 
-// GetInitialKeybindings is a function.
+// validateKeybindings checks that every remappable action has a key we
+// understand, and that no two actions share a key where they're both active:
+// the same view, or the same context of the main view
+func (gui *Gui) validateKeybindings() error {
+	problems := []string{}
+	keyNameSets := [][]*Binding{}
+	initialBindings := gui.namedKeybindings(gui.getInitialKeybindings())
+	for _, binding := range initialBindings {
+		if _, err := gui.getKey(binding.Name); err != nil && !utils.IncludesString(problems, err.Error()) {
+			problems = append(problems, err.Error())
+		}
+	}
+	keyNameSets = append(keyNameSets, initialBindings)
+	for _, contexts := range gui.getContextMap() {
+		for _, contextBindings := range contexts {
+			contextBindings = gui.namedKeybindings(contextBindings)
+			for _, binding := range contextBindings {
+				if _, err := gui.getKey(binding.Name); err != nil && !utils.IncludesString(problems, err.Error()) {
+					problems = append(problems, err.Error())
+				}
+			}
+			keyNameSets = append(keyNameSets, append(contextBindings, initialBindings...))
+		}
+	}
+
+	for _, bindings := range keyNameSets {
+		// maps each view and key to the name of the first action bound to it
+		seen := map[string]string{}
+		for _, binding := range bindings {
+			key, err := gui.getKey(binding.Name)
+			if err != nil || key == nil {
+				continue
+			}
+			seenKey := binding.ViewName + "/" + getKeyDisplay(key)
+			if name, ok := seen[seenKey]; ok && name != binding.Name {
+				problem := gui.Tr.TemplateLocalize(
+					"KeybindingConflict",
+					Teml{
+						"key":   getKeyDisplay(key),
+						"view":  gui.viewDisplayName(binding.ViewName),
+						"first": name,
+						"other": binding.Name,
+					},
+				)
+				if !utils.IncludesString(problems, problem) {
+					problems = append(problems, problem)
+				}
+				continue
+			}
+			seen[seenKey] = binding.Name
+		}
+	}
+
+	if len(problems) > 0 {
+		return &KeybindingConfigError{Problems: problems}
+	}
+	return nil
+}
+
+// namedKeybindings returns the bindings that can be remapped in the config
+func (gui *Gui) namedKeybindings(bindings []*Binding) []*Binding {
+	named := []*Binding{}
+	for _, binding := range bindings {
+		if binding.Name != "" {
+			named = append(named, binding)
+		}
+	}
+	return named
+}
+
+func (gui *Gui) viewDisplayName(viewName string) string {
+	if viewName == "" {
+		return "global"
+	}
+	return viewName
+}
+
+// GetInitialKeybindings returns the bindings that are always active, with the
+// keys the user has configured
 func (gui *Gui) GetInitialKeybindings() []*Binding {
+	// custom commands take precedence over any built-in binding for the same
+	// key. gocui runs the first binding of a key in a view but the last global
+	// one, so they go first in views and last globally
+	bindings := []*Binding{}
+	globalCustomBindings := []*Binding{}
+	for _, binding := range gui.getCustomCommandKeybindings() {
+		if binding.ViewName == "" {
+			globalCustomBindings = append(globalCustomBindings, binding)
+		} else {
+			bindings = append(bindings, binding)
+		}
+	}
+	bindings = append(bindings, gui.resolveKeybindings(gui.getInitialKeybindings())...)
+	return append(bindings, globalCustomBindings...)
+}
+
+func (gui *Gui) getInitialKeybindings() []*Binding {
 	bindings := []*Binding{
 		{
 			ViewName: "",
-			Key:      'q',
+			Name:     "universal.quit",
 			Modifier: gocui.ModNone,
 			Handler:  gui.quit,
 		}, {
 			ViewName: "",
-			Key:      gocui.KeyCtrlC,
+			Name:     "universal.quit-alt1",
 			Modifier: gocui.ModNone,
 			Handler:  gui.quit,
 		}, {
 			ViewName: "",
-			Key:      gocui.KeyEsc,
+			Name:     "universal.return",
 			Modifier: gocui.ModNone,
 			Handler:  gui.quit,
 		}, {
 			ViewName:    "",
-			Key:         gocui.KeyPgup,
+			Name:        "universal.scrollUpMain",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.scrollUpMain,
 			Alternative: "fn+up",
 		}, {
 			ViewName:    "",
-			Key:         gocui.KeyPgdn,
+			Name:        "universal.scrollDownMain",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.scrollDownMain,
 			Alternative: "fn+down",
 		}, {
 			ViewName: "",
-			Key:      'K',
+			Name:     "universal.scrollUpMain-alt1",
 			Modifier: gocui.ModNone,
 			Handler:  gui.scrollUpMain,
 		}, {
 			ViewName: "",
-			Key:      'J',
+			Name:     "universal.scrollDownMain-alt1",
 			Modifier: gocui.ModNone,
 			Handler:  gui.scrollDownMain,
 		}, {
 			ViewName: "",
-			Key:      gocui.KeyCtrlU,
+			Name:     "universal.scrollUpMain-alt2",
 			Modifier: gocui.ModNone,
 			Handler:  gui.scrollUpMain,
 		}, {
 			ViewName: "",
-			Key:      gocui.KeyCtrlD,
+			Name:     "universal.scrollDownMain-alt2",
 			Modifier: gocui.ModNone,
 			Handler:  gui.scrollDownMain,
 		}, {
 			ViewName:    "",
-			Key:         'm',
+			Name:        "universal.createRebaseOptionsMenu",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateRebaseOptionsMenu,
 			Description: gui.Tr.SLocalize("ViewMergeRebaseOptions"),
 		}, {
 			ViewName:    "",
-			Key:         'P',
+			Name:        "universal.pushFiles",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.pushFiles,
 			Description: gui.Tr.SLocalize("push"),
 		}, {
 			ViewName:    "",
-			Key:         'p',
+			Name:        "universal.createPushMenu",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreatePushMenu,
+			Description: gui.Tr.SLocalize("viewPushOptions"),
+		}, {
+			ViewName:    "",
+			Name:        "universal.pullFiles",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.pullFiles,
 			Description: gui.Tr.SLocalize("pull"),
 		}, {
 			ViewName:    "",
-			Key:         'R',
+			Name:        "universal.createPullMenu",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreatePullMenu,
+			Description: gui.Tr.SLocalize("viewPullOptions"),
+		}, {
+			ViewName:    "",
+			Name:        "universal.refresh",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleRefresh,
 			Description: gui.Tr.SLocalize("refresh"),
+		}, {
+			ViewName:    "",
+			Name:        "universal.toggleSideBySideDiff",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleSideBySideDiff,
+			Description: gui.Tr.SLocalize("toggleSideBySideDiff"),
+		}, {
+			ViewName:    "",
+			Name:        "universal.diffOptions",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateDiffOptionsMenu,
+			Description: gui.Tr.SLocalize("viewDiffOptions"),
+		}, {
+			ViewName:    "",
+			Name:        "universal.nextScreenMode",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleNextScreenMode,
+			Description: gui.Tr.SLocalize("nextScreenMode"),
+		}, {
+			ViewName:    "",
+			Name:        "universal.prevScreenMode",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handlePrevScreenMode,
+			Description: gui.Tr.SLocalize("prevScreenMode"),
+		}, {
+			ViewName:    "",
+			Name:        "universal.toggleCommandLog",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleCommandLog,
+			Description: gui.Tr.SLocalize("toggleCommandLog"),
+		}, {
+			ViewName:    "",
+			Name:        "universal.focusCommandLog",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleFocusCommandLog,
+			Description: gui.Tr.SLocalize("focusCommandLog"),
+		}, {
+			ViewName:    "",
+			Name:        "universal.cancelCommand",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCancelCommands,
+			Description: gui.Tr.SLocalize("cancelCommand"),
+		}, {
+			ViewName:    "",
+			Name:        "universal.startSearch",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleStartSearch,
+			Description: gui.Tr.SLocalize("startSearch"),
+		}, {
+			ViewName:    "",
+			Name:        "universal.startSearchMain",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleStartSearchMain,
+			Description: gui.Tr.SLocalize("startSearchMain"),
 		}, {
 			ViewName: "",
-			Key:      'x',
+			Name:     "universal.optionMenu",
 			Modifier: gocui.ModNone,
 			Handler:  gui.handleCreateOptionsMenu,
 		}, {
 			ViewName:    "status",
-			Key:         'e',
+			Name:        "status.editConfig",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleEditConfig,
 			Description: gui.Tr.SLocalize("EditConfig"),
 		}, {
 			ViewName:    "status",
-			Key:         'o',
+			Name:        "status.openConfig",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleOpenConfig,
 			Description: gui.Tr.SLocalize("OpenConfig"),
 		}, {
 			ViewName:    "status",
-			Key:         'u',
+			Name:        "status.checkForUpdate",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCheckForUpdate,
 			Description: gui.Tr.SLocalize("checkForUpdate"),
 		}, {
 			ViewName:    "status",
-			Key:         's',
+			Name:        "status.recentRepos",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateRecentReposMenu,
 			Description: gui.Tr.SLocalize("SwitchRepo"),
 		},
 		{
 			ViewName:    "files",
-			Key:         'c',
+			Name:        "files.commitChanges",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitPress,
 			Description: gui.Tr.SLocalize("CommitChanges"),
 		},
 		{
 			ViewName:    "files",
-			Key:         'w',
+			Name:        "files.commitChangesWithoutHook",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleWIPCommitPress,
 			Description: gui.Tr.SLocalize("commitChangesWithoutHook"),
 		}, {
 			ViewName:    "files",
-			Key:         'A',
+			Name:        "files.amendLastCommit",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleAmendCommitPress,
 			Description: gui.Tr.SLocalize("AmendLastCommit"),
 		}, {
 			ViewName:    "files",
-			Key:         'C',
+			Name:        "files.commitChangesWithEditor",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitEditorPress,
 			Description: gui.Tr.SLocalize("CommitChangesWithEditor"),
 		}, {
 			ViewName:    "files",
-			Key:         gocui.KeySpace,
+			Name:        "files.toggleStaged",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleFilePress,
 			Description: gui.Tr.SLocalize("toggleStaged"),
 		}, {
 			ViewName:    "files",
-			Key:         'd',
+			Name:        "files.viewDiscardOptions",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateDiscardMenu,
 			Description: gui.Tr.SLocalize("viewDiscardOptions"),
 		}, {
 			ViewName:    "files",
-			Key:         'e',
+			Name:        "files.edit",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleFileEdit,
 			Description: gui.Tr.SLocalize("editFile"),
 		}, {
 			ViewName:    "files",
-			Key:         'o',
+			Name:        "files.openFile",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleFileOpen,
 			Description: gui.Tr.SLocalize("openFile"),
 		}, {
 			ViewName:    "files",
-			Key:         'i',
+			Name:        "files.ignoreFile",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleIgnoreFile,
 			Description: gui.Tr.SLocalize("ignoreFile"),
 		}, {
 			ViewName:    "files",
-			Key:         'r',
+			Name:        "files.refreshFiles",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleRefreshFiles,
 			Description: gui.Tr.SLocalize("refreshFiles"),
 		}, {
 			ViewName:    "files",
-			Key:         's',
+			Name:        "files.stashAllChanges",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleStashChanges,
 			Description: gui.Tr.SLocalize("stashAllChanges"),
 		}, {
 			ViewName:    "files",
-			Key:         'S',
+			Name:        "files.viewStashOptions",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateStashMenu,
 			Description: gui.Tr.SLocalize("viewStashOptions"),
 		}, {
 			ViewName:    "files",
-			Key:         'a',
+			Name:        "files.toggleStagedAll",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleStageAll,
 			Description: gui.Tr.SLocalize("toggleStagedAll"),
 		}, {
 			ViewName:    "files",
-			Key:         't',
+			Name:        "files.addPatch",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleAddPatch,
 			Description: gui.Tr.SLocalize("addPatch"),
 		}, {
 			ViewName:    "files",
-			Key:         'D',
+			Name:        "files.viewResetOptions",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateResetMenu,
 			Description: gui.Tr.SLocalize("viewResetOptions"),
 		}, {
 			ViewName:    "files",
-			Key:         gocui.KeyEnter,
+			Name:        "files.goInto",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleEnterFile,
 			Description: gui.Tr.SLocalize("StageLines"),
 		}, {
 			ViewName:    "files",
-			Key:         'f',
+			Name:        "files.fetch",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleGitFetch,
 			Description: gui.Tr.SLocalize("fetch"),
 		}, {
 			ViewName:    "files",
-			Key:         'X',
+			Name:        "files.executeCustomCommand",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCustomCommand,
 			Description: gui.Tr.SLocalize("executeCustomCommand"),
 		}, {
 			ViewName:    "branches",
-			Key:         gocui.KeySpace,
+			Name:        "branches.checkoutBranch",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleBranchPress,
 			Description: gui.Tr.SLocalize("checkout"),
 		}, {
 			ViewName:    "branches",
-			Key:         'o',
+			Name:        "branches.createPullRequest",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreatePullRequestPress,
 			Description: gui.Tr.SLocalize("createPullRequest"),
 		}, {
 			ViewName:    "branches",
-			Key:         'c',
+			Name:        "branches.checkoutBranchByName",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCheckoutByName,
 			Description: gui.Tr.SLocalize("checkoutByName"),
 		}, {
 			ViewName:    "branches",
-			Key:         'F',
+			Name:        "branches.forceCheckoutBranch",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleForceCheckout,
 			Description: gui.Tr.SLocalize("forceCheckout"),
 		}, {
 			ViewName:    "branches",
-			Key:         'n',
+			Name:        "branches.newBranch",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleNewBranch,
 			Description: gui.Tr.SLocalize("newBranch"),
 		}, {
 			ViewName:    "branches",
-			Key:         'd',
+			Name:        "branches.deleteBranch",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleDeleteBranch,
 			Description: gui.Tr.SLocalize("deleteBranch"),
 		}, {
 			ViewName:    "branches",
-			Key:         'r',
+			Name:        "branches.rebaseBranch",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleRebase,
 			Description: gui.Tr.SLocalize("rebaseBranch"),
 		}, {
 			ViewName:    "branches",
-			Key:         'M',
+			Name:        "branches.mergeIntoCurrentBranch",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleMerge,
 			Description: gui.Tr.SLocalize("mergeIntoCurrentBranch"),
 		}, {
 			ViewName:    "branches",
-			Key:         'f',
+			Name:        "branches.fastForward",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleFastForward,
 			Description: gui.Tr.SLocalize("FastForward"),
 		}, {
 			ViewName:    "commits",
-			Key:         's',
+			Name:        "commits.squashDown",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitSquashDown,
 			Description: gui.Tr.SLocalize("squashDown"),
 		}, {
 			ViewName:    "commits",
-			Key:         'r',
+			Name:        "commits.renameCommit",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleRenameCommit,
 			Description: gui.Tr.SLocalize("renameCommit"),
 		}, {
 			ViewName:    "commits",
-			Key:         'R',
+			Name:        "commits.renameCommitWithEditor",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleRenameCommitEditor,
 			Description: gui.Tr.SLocalize("renameCommitEditor"),
 		}, {
 			ViewName:    "commits",
-			Key:         'g',
+			Name:        "commits.viewResetOptions",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateCommitResetMenu,
 			Description: gui.Tr.SLocalize("resetToThisCommit"),
 		}, {
 			ViewName:    "commits",
-			Key:         'f',
+			Name:        "commits.markCommitAsFixup",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitFixup,
 			Description: gui.Tr.SLocalize("fixupCommit"),
 		}, {
 			ViewName:    "commits",
-			Key:         'F',
+			Name:        "commits.createFixupCommit",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateFixupCommit,
 			Description: gui.Tr.SLocalize("createFixupCommit"),
 		}, {
 			ViewName:    "commits",
-			Key:         'S',
+			Name:        "commits.squashAboveCommits",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleSquashAllAboveFixupCommits,
 			Description: gui.Tr.SLocalize("squashAboveCommits"),
 		}, {
 			ViewName:    "commits",
-			Key:         'd',
+			Name:        "commits.deleteCommit",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitDelete,
 			Description: gui.Tr.SLocalize("deleteCommit"),
 		}, {
 			ViewName:    "commits",
-			Key:         gocui.KeyCtrlJ,
+			Name:        "commits.moveDownCommit",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitMoveDown,
 			Description: gui.Tr.SLocalize("moveDownCommit"),
 		}, {
 			ViewName:    "commits",
-			Key:         gocui.KeyCtrlK,
+			Name:        "commits.moveUpCommit",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitMoveUp,
 			Description: gui.Tr.SLocalize("moveUpCommit"),
 		}, {
 			ViewName:    "commits",
-			Key:         'e',
+			Name:        "commits.editCommit",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitEdit,
 			Description: gui.Tr.SLocalize("editCommit"),
 		}, {
 			ViewName:    "commits",
-			Key:         'A',
+			Name:        "commits.amendToCommit",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitAmendTo,
 			Description: gui.Tr.SLocalize("amendToCommit"),
 		}, {
 			ViewName:    "commits",
-			Key:         'p',
+			Name:        "commits.pickCommit",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitPick,
 			Description: gui.Tr.SLocalize("pickCommit"),
 		}, {
 			ViewName:    "commits",
-			Key:         't',
+			Name:        "commits.revertCommit",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitRevert,
 			Description: gui.Tr.SLocalize("revertCommit"),
 		}, {
 			ViewName:    "commits",
-			Key:         'c',
+			Name:        "commits.cherryPickCopy",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCopyCommit,
 			Description: gui.Tr.SLocalize("cherryPickCopy"),
 		}, {
 			ViewName:    "commits",
-			Key:         'C',
+			Name:        "commits.cherryPickCopyRange",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCopyCommitRange,
 			Description: gui.Tr.SLocalize("cherryPickCopyRange"),
 		}, {
 			ViewName:    "commits",
-			Key:         'v',
+			Name:        "commits.pasteCommits",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.HandlePasteCommits,
 			Description: gui.Tr.SLocalize("pasteCommits"),
 		}, {
 			ViewName:    "commits",
-			Key:         gocui.KeyEnter,
+			Name:        "commits.viewCommitFiles",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleSwitchToCommitFilesPanel,
 			Description: gui.Tr.SLocalize("viewCommitFiles"),
 		}, {
 			ViewName:    "commits",
-			Key:         gocui.KeySpace,
+			Name:        "commits.toggleDiffCommit",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleToggleDiffCommit,
 			Description: gui.Tr.SLocalize("CommitsDiff"),
 		}, {
 			ViewName:    "stash",
-			Key:         gocui.KeySpace,
+			Name:        "stash.applyStash",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleStashApply,
 			Description: gui.Tr.SLocalize("apply"),
 		}, {
 			ViewName:    "stash",
-			Key:         'g',
+			Name:        "stash.popStash",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleStashPop,
 			Description: gui.Tr.SLocalize("pop"),
 		}, {
 			ViewName:    "stash",
-			Key:         'd',
+			Name:        "stash.dropStash",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleStashDrop,
 			Description: gui.Tr.SLocalize("drop"),
 		}, {
 			ViewName: "commitMessage",
-			Key:      gocui.KeyEnter,
+			Name:     "universal.confirm",
 			Modifier: gocui.ModNone,
 			Handler:  gui.handleCommitConfirm,
 		}, {
 			ViewName: "commitMessage",
-			Key:      gocui.KeyEsc,
+			Name:     "universal.return",
 			Modifier: gocui.ModNone,
 			Handler:  gui.handleCommitClose,
+		}, {
+			ViewName: "commitMessage",
+			Name:     "universal.togglePanel",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleSwitchToCommitDescription,
+		}, {
+			ViewName: "commitMessage",
+			Name:     "universal.prevItem",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleCommitMessageHistoryPrev,
+		}, {
+			ViewName: "commitMessage",
+			Name:     "universal.nextItem",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleCommitMessageHistoryNext,
+		}, {
+			ViewName: "commitMessage",
+			Name:     "commitMessage.addTrailer",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleCreateTrailersMenu,
+		}, {
+			ViewName: "commitMessage",
+			Name:     "commitMessage.toggleSkipHooks",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleToggleSkipHooks,
+		}, {
+			ViewName: "commitDescription",
+			Name:     "commitMessage.newLine",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleCommitDescriptionNewLine,
+		}, {
+			ViewName: "commitDescription",
+			Name:     "commitMessage.confirm",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleCommitConfirm,
+		}, {
+			ViewName: "commitDescription",
+			Name:     "universal.return",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleCommitClose,
+		}, {
+			ViewName: "commitDescription",
+			Name:     "universal.togglePanel",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleSwitchToCommitMessage,
+		}, {
+			ViewName: "commitDescription",
+			Name:     "commitMessage.addTrailer",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleCreateTrailersMenu,
+		}, {
+			ViewName: "commitDescription",
+			Name:     "commitMessage.toggleSkipHooks",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleToggleSkipHooks,
+		}, {
+			ViewName: "hookOutput",
+			Name:     "universal.return",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleHookOutputClose,
+		}, {
+			ViewName: "hookOutput",
+			Name:     "universal.confirm",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleHookOutputRetry,
+		}, {
+			ViewName: "hookOutput",
+			Name:     "universal.prevItem",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleHookOutputScrollUp,
+		}, {
+			ViewName: "hookOutput",
+			Name:     "universal.nextItem",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleHookOutputScrollDown,
+		}, {
+			ViewName: "hookOutput",
+			Name:     "universal.prevItem-alt",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleHookOutputScrollUp,
+		}, {
+			ViewName: "hookOutput",
+			Name:     "universal.nextItem-alt",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleHookOutputScrollDown,
+		}, {
+			ViewName:    "commandLog",
+			Name:        "universal.select",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCommandLogToggleExpand,
+			Description: gui.Tr.SLocalize("toggleCommandOutput"),
+		}, {
+			ViewName: "commandLog",
+			Name:     "universal.confirm",
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleCommandLogToggleExpand,
+		}, {
+			ViewName:    "commandLog",
+			Name:        "universal.return",
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCommandLogEscape,
+			Description: gui.Tr.SLocalize("goBack"),
 		}, {
 			ViewName: "credentials",
-			Key:      gocui.KeyEnter,
+			Name:     "universal.confirm",
 			Modifier: gocui.ModNone,
 			Handler:  gui.handleSubmitCredential,
 		}, {
 			ViewName: "credentials",
-			Key:      gocui.KeyEsc,
+			Name:     "universal.return",
 			Modifier: gocui.ModNone,
 			Handler:  gui.handleCloseCredentialsView,
 		}, {
 			ViewName: "menu",
-			Key:      gocui.KeyEsc,
+			Name:     "universal.return",
 			Modifier: gocui.ModNone,
 			Handler:  gui.handleMenuClose,
 		}, {
 			ViewName: "menu",
-			Key:      'q',
+			Name:     "universal.quit",
 			Modifier: gocui.ModNone,
 			Handler:  gui.handleMenuClose,
 		}, {
@@ -499,26 +927,26 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Handler:  gui.handleDonate,
 		}, {
 			ViewName:    "commitFiles",
-			Key:         gocui.KeyEsc,
+			Name:        "universal.return",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleSwitchToCommitsPanel,
 			Description: gui.Tr.SLocalize("goBack"),
 		}, {
 			ViewName:    "commitFiles",
-			Key:         'c',
+			Name:        "commitFiles.checkoutCommitFile",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCheckoutCommitFile,
 			Description: gui.Tr.SLocalize("checkoutCommitFile"),
 		}, {
 			ViewName:    "commitFiles",
-			Key:         'd',
+			Name:        "commitFiles.discardOldFileChange",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleDiscardOldFileChange,
 			Description: gui.Tr.SLocalize("discardOldFileChange"),
 		},
 		{
 			ViewName:    "commitFiles",
-			Key:         'o',
+			Name:        "commitFiles.openFile",
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleOpenOldCommitFile,
 			Description: gui.Tr.SLocalize("openFile"),
@@ -527,17 +955,25 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 
 	for _, viewName := range []string{"status", "branches", "files", "commits", "commitFiles", "stash", "menu"} {
 		bindings = append(bindings, []*Binding{
-			{ViewName: viewName, Key: gocui.KeyTab, Modifier: gocui.ModNone, Handler: gui.nextView},
-			{ViewName: viewName, Key: gocui.KeyArrowLeft, Modifier: gocui.ModNone, Handler: gui.previousView},
-			{ViewName: viewName, Key: gocui.KeyArrowRight, Modifier: gocui.ModNone, Handler: gui.nextView},
-			{ViewName: viewName, Key: 'h', Modifier: gocui.ModNone, Handler: gui.previousView},
-			{ViewName: viewName, Key: 'l', Modifier: gocui.ModNone, Handler: gui.nextView},
+			{ViewName: viewName, Name: "universal.togglePanel", Modifier: gocui.ModNone, Handler: gui.nextView},
+			{ViewName: viewName, Name: "universal.prevBlock", Modifier: gocui.ModNone, Handler: gui.previousView},
+			{ViewName: viewName, Name: "universal.nextBlock", Modifier: gocui.ModNone, Handler: gui.nextView},
+			{ViewName: viewName, Name: "universal.prevBlock-alt", Modifier: gocui.ModNone, Handler: gui.previousView},
+			{ViewName: viewName, Name: "universal.nextBlock-alt", Modifier: gocui.ModNone, Handler: gui.nextView},
 		}...)
 	}
 
+	for _, viewName := range []string{"files", "branches", "commits", "commitFiles", "stash", "menu"} {
+		bindings = append(bindings, &Binding{ViewName: viewName, Name: "universal.filter", Modifier: gocui.ModNone, Handler: gui.handleOpenFilter, Description: gui.Tr.SLocalize("filterList")})
+	}
+	bindings = append(bindings, []*Binding{
+		{ViewName: "filter", Name: "universal.confirm", Modifier: gocui.ModNone, Handler: gui.handleFilterConfirm},
+		{ViewName: "filter", Name: "universal.return", Modifier: gocui.ModNone, Handler: gui.handleFilterCancel},
+	}...)
+
 	// Appends keybindings to jump to a particular sideView using numbers
-	for i, viewName := range []string{"status", "files", "branches", "commits", "stash"} {
-		bindings = append(bindings, &Binding{ViewName: "", Key: rune(i+1) + '0', Modifier: gocui.ModNone, Handler: gui.goToSideView(viewName)})
+	for _, viewName := range []string{"status", "files", "branches", "commits", "stash"} {
+		bindings = append(bindings, &Binding{ViewName: "", Name: "universal.goTo" + strings.Title(viewName), Modifier: gocui.ModNone, Handler: gui.goToSideView(viewName)})
 	}
 
 	listPanelMap := map[string]struct {
@@ -547,20 +983,21 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 	}{
 		"menu":        {prevLine: gui.handleMenuPrevLine, nextLine: gui.handleMenuNextLine, focus: gui.handleMenuSelect},
 		"files":       {prevLine: gui.handleFilesPrevLine, nextLine: gui.handleFilesNextLine, focus: gui.handleFilesFocus},
-		"branches":    {prevLine: gui.handleBranchesPrevLine, nextLine: gui.handleBranchesNextLine, focus: gui.handleBranchSelect},
-		"commits":     {prevLine: gui.handleCommitsPrevLine, nextLine: gui.handleCommitsNextLine, focus: gui.handleCommitSelect},
-		"stash":       {prevLine: gui.handleStashPrevLine, nextLine: gui.handleStashNextLine, focus: gui.handleStashEntrySelect},
-		"status":      {focus: gui.handleStatusSelect},
-		"commitFiles": {prevLine: gui.handleCommitFilesPrevLine, nextLine: gui.handleCommitFilesNextLine, focus: gui.handleCommitFileSelect},
+		"branches":    {prevLine: gui.handleBranchesPrevLine, nextLine: gui.handleBranchesNextLine, focus: gui.handleListClick},
+		"commits":     {prevLine: gui.handleCommitsPrevLine, nextLine: gui.handleCommitsNextLine, focus: gui.handleListClick},
+		"stash":       {prevLine: gui.handleStashPrevLine, nextLine: gui.handleStashNextLine, focus: gui.handleListClick},
+		"status":      {focus: gui.handleListClick},
+		"commitFiles": {prevLine: gui.handleCommitFilesPrevLine, nextLine: gui.handleCommitFilesNextLine, focus: gui.handleListClick},
+		"commandLog":  {prevLine: gui.handleCommandLogPrevLine, nextLine: gui.handleCommandLogNextLine, focus: gui.handleCommandLogClick},
 	}
 
 	for viewName, functions := range listPanelMap {
 		bindings = append(bindings, []*Binding{
-			{ViewName: viewName, Key: 'k', Modifier: gocui.ModNone, Handler: functions.prevLine},
-			{ViewName: viewName, Key: gocui.KeyArrowUp, Modifier: gocui.ModNone, Handler: functions.prevLine},
+			{ViewName: viewName, Name: "universal.prevItem-alt", Modifier: gocui.ModNone, Handler: functions.prevLine},
+			{ViewName: viewName, Name: "universal.prevItem", Modifier: gocui.ModNone, Handler: functions.prevLine},
 			{ViewName: viewName, Key: gocui.MouseWheelUp, Modifier: gocui.ModNone, Handler: functions.prevLine},
-			{ViewName: viewName, Key: 'j', Modifier: gocui.ModNone, Handler: functions.nextLine},
-			{ViewName: viewName, Key: gocui.KeyArrowDown, Modifier: gocui.ModNone, Handler: functions.nextLine},
+			{ViewName: viewName, Name: "universal.nextItem-alt", Modifier: gocui.ModNone, Handler: functions.nextLine},
+			{ViewName: viewName, Name: "universal.nextItem", Modifier: gocui.ModNone, Handler: functions.nextLine},
 			{ViewName: viewName, Key: gocui.MouseWheelDown, Modifier: gocui.ModNone, Handler: functions.nextLine},
 			{ViewName: viewName, Key: gocui.MouseLeft, Modifier: gocui.ModNone, Handler: functions.focus},
 		}...)
@@ -580,6 +1017,10 @@ func (gui *Gui) GetCurrentKeybindings() []*Binding {
 }
 
 func (gui *Gui) keybindings(g *gocui.Gui) error {
+	if err := gui.validateKeybindings(); err != nil {
+		return err
+	}
+
 	bindings := gui.GetInitialKeybindings()
 
 	for _, binding := range bindings {
@@ -593,7 +1034,19 @@ func (gui *Gui) keybindings(g *gocui.Gui) error {
 	return nil
 }
 
+// GetContextMap returns the bindings of each context of the views that have
+// them, with the keys the user has configured
 func (gui *Gui) GetContextMap() map[string]map[string][]*Binding {
+	contextMap := gui.getContextMap()
+	for _, contexts := range contextMap {
+		for context, bindings := range contexts {
+			contexts[context] = gui.resolveKeybindings(bindings)
+		}
+	}
+	return contextMap
+}
+
+func (gui *Gui) getContextMap() map[string]map[string][]*Binding {
 	return map[string]map[string][]*Binding{
 		"main": {
 			"normal": {
@@ -611,35 +1064,40 @@ func (gui *Gui) GetContextMap() map[string]map[string][]*Binding {
 					Handler:     gui.scrollUpMain,
 					Description: gui.Tr.SLocalize("ScrollUp"),
 					Alternative: "fn+down",
+				}, {
+					ViewName: "main",
+					Key:      gocui.MouseLeft,
+					Modifier: gocui.ModNone,
+					Handler:  gui.handleMainClick,
 				},
 			},
 			"staging": {
 				{
 					ViewName:    "main",
-					Key:         gocui.KeyEsc,
+					Name:        "universal.return",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handleStagingEscape,
 					Description: gui.Tr.SLocalize("EscapeStaging"),
 				}, {
 					ViewName:    "main",
-					Key:         gocui.KeyArrowUp,
+					Name:        "universal.prevItem",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handleStagingPrevLine,
 					Description: gui.Tr.SLocalize("PrevLine"),
 				}, {
 					ViewName:    "main",
-					Key:         gocui.KeyArrowDown,
+					Name:        "universal.nextItem",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handleStagingNextLine,
 					Description: gui.Tr.SLocalize("NextLine"),
 				}, {
 					ViewName: "main",
-					Key:      'k',
+					Name:     "universal.prevItem-alt",
 					Modifier: gocui.ModNone,
 					Handler:  gui.handleStagingPrevLine,
 				}, {
 					ViewName: "main",
-					Key:      'j',
+					Name:     "universal.nextItem-alt",
 					Modifier: gocui.ModNone,
 					Handler:  gui.handleStagingNextLine,
 				}, {
@@ -652,37 +1110,47 @@ func (gui *Gui) GetContextMap() map[string]map[string][]*Binding {
 					Key:      gocui.MouseWheelDown,
 					Modifier: gocui.ModNone,
 					Handler:  gui.handleStagingNextLine,
+				}, {
+					ViewName: "main",
+					Key:      gocui.MouseLeft,
+					Modifier: gocui.ModNone,
+					Handler:  gui.handleStagingClick,
+				}, {
+					ViewName: "main",
+					Key:      gocui.MouseLeft,
+					Modifier: modMotion,
+					Handler:  gui.handleStagingDrag,
 				}, {
 					ViewName:    "main",
-					Key:         gocui.KeyArrowLeft,
+					Name:        "universal.prevBlock",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handleStagingPrevHunk,
 					Description: gui.Tr.SLocalize("PrevHunk"),
 				}, {
 					ViewName:    "main",
-					Key:         gocui.KeyArrowRight,
+					Name:        "universal.nextBlock",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handleStagingNextHunk,
 					Description: gui.Tr.SLocalize("NextHunk"),
 				}, {
 					ViewName: "main",
-					Key:      'h',
+					Name:     "universal.prevBlock-alt",
 					Modifier: gocui.ModNone,
 					Handler:  gui.handleStagingPrevHunk,
 				}, {
 					ViewName: "main",
-					Key:      'l',
+					Name:     "universal.nextBlock-alt",
 					Modifier: gocui.ModNone,
 					Handler:  gui.handleStagingNextHunk,
 				}, {
 					ViewName:    "main",
-					Key:         gocui.KeySpace,
+					Name:        "main.stageLine",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handleStageLine,
 					Description: gui.Tr.SLocalize("StageLine"),
 				}, {
 					ViewName:    "main",
-					Key:         'a',
+					Name:        "main.stageHunk",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handleStageHunk,
 					Description: gui.Tr.SLocalize("StageHunk"),
@@ -691,45 +1159,45 @@ func (gui *Gui) GetContextMap() map[string]map[string][]*Binding {
 			"merging": {
 				{
 					ViewName:    "main",
-					Key:         gocui.KeyEsc,
+					Name:        "universal.return",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handleEscapeMerge,
 					Description: gui.Tr.SLocalize("EscapeStaging"),
 				}, {
 					ViewName:    "main",
-					Key:         gocui.KeySpace,
+					Name:        "main.pickHunk",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handlePickHunk,
 					Description: gui.Tr.SLocalize("PickHunk"),
 				}, {
 					ViewName:    "main",
-					Key:         'b',
+					Name:        "main.pickBothHunks",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handlePickBothHunks,
 					Description: gui.Tr.SLocalize("PickBothHunks"),
 				}, {
 					ViewName:    "main",
-					Key:         gocui.KeyArrowLeft,
+					Name:        "universal.prevBlock",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handleSelectPrevConflict,
 					Description: gui.Tr.SLocalize("PrevConflict"),
 				}, {
 					ViewName: "main",
-					Key:      gocui.KeyArrowRight,
+					Name:     "universal.nextBlock",
 					Modifier: gocui.ModNone,
 					Handler:  gui.handleSelectNextConflict,
 
 					Description: gui.Tr.SLocalize("NextConflict"),
 				}, {
 					ViewName: "main",
-					Key:      gocui.KeyArrowUp,
+					Name:     "universal.prevItem",
 
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handleSelectTop,
 					Description: gui.Tr.SLocalize("SelectTop"),
 				}, {
 					ViewName:    "main",
-					Key:         gocui.KeyArrowDown,
+					Name:        "universal.nextItem",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handleSelectBottom,
 					Description: gui.Tr.SLocalize("SelectBottom"),
@@ -745,27 +1213,32 @@ func (gui *Gui) GetContextMap() map[string]map[string][]*Binding {
 					Handler:  gui.handleSelectBottom,
 				}, {
 					ViewName: "main",
-					Key:      'h',
+					Key:      gocui.MouseLeft,
+					Modifier: gocui.ModNone,
+					Handler:  gui.handleMergeClick,
+				}, {
+					ViewName: "main",
+					Name:     "universal.prevBlock-alt",
 					Modifier: gocui.ModNone,
 					Handler:  gui.handleSelectPrevConflict,
 				}, {
 					ViewName: "main",
-					Key:      'l',
+					Name:     "universal.nextBlock-alt",
 					Modifier: gocui.ModNone,
 					Handler:  gui.handleSelectNextConflict,
 				}, {
 					ViewName: "main",
-					Key:      'k',
+					Name:     "universal.prevItem-alt",
 					Modifier: gocui.ModNone,
 					Handler:  gui.handleSelectTop,
 				}, {
 					ViewName: "main",
-					Key:      'j',
+					Name:     "universal.nextItem-alt",
 					Modifier: gocui.ModNone,
 					Handler:  gui.handleSelectBottom,
 				}, {
 					ViewName:    "main",
-					Key:         'z',
+					Name:        "main.undo",
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handlePopFileSnapshot,
 					Description: gui.Tr.SLocalize("Undo"),
@@ -0,0 +1,171 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// screenMode is how much of the screen the focused panel takes up
+type screenMode int
+
+const (
+	// screenModeNormal gives the side panels a third of the screen and the main
+	// panel the rest
+	screenModeNormal screenMode = iota
+	// screenModeHalf gives a focused side panel half of the screen
+	screenModeHalf
+	// screenModeFull gives the focused panel the whole screen
+	screenModeFull
+)
+
+// the number of screen modes we cycle through
+const screenModeCount = 3
+
+// the shortest the main panel can be for the command log to fit below it
+const minMainHeightForCommandLog = 10
+
+type dimensions struct {
+	x0, y0, x1, y1 int
+}
+
+func (gui *Gui) handleNextScreenMode(g *gocui.Gui, v *gocui.View) error {
+	gui.State.ScreenMode = (gui.State.ScreenMode + 1) % screenModeCount
+	return nil
+}
+
+func (gui *Gui) handlePrevScreenMode(g *gocui.Gui, v *gocui.View) error {
+	gui.State.ScreenMode = (gui.State.ScreenMode + screenModeCount - 1) % screenModeCount
+	return nil
+}
+
+// isPortrait tells us whether to stack the main panel below the side panels,
+// which is up to the gui.portraitMode config: 'always', 'never', or 'auto' to
+// do it in a terminal that's too narrow to fit the two side by side
+func (gui *Gui) isPortrait(width, height int) bool {
+	switch gui.Config.GetUserConfig().GetString("gui.portraitMode") {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return width <= 84 && height > 45
+}
+
+// getViewDimensions works out where the side panels and the main panel go,
+// given the screen mode and which of them has focus. Views that the screen
+// mode leaves no room for are put off the screen
+func (gui *Gui) getViewDimensions(width, height int, currentCyclebleView string, mainFocused bool) map[string]dimensions {
+	// the bottom line is for the options and app status
+	bottom := height - 2
+	hidden := dimensions{width, height, width * 2, height * 2}
+
+	portrait := gui.isPortrait(width, height)
+	// the size of the side panels, across for a landscape layout or down for
+	// a portrait one
+	size := width
+	if portrait {
+		size = height - 1
+	}
+	sideSize := size / 3
+	switch gui.State.ScreenMode {
+	case screenModeHalf:
+		if !mainFocused {
+			sideSize = size / 2
+		}
+	case screenModeFull:
+		if mainFocused {
+			sideSize = 0
+		} else {
+			sideSize = size
+		}
+	}
+
+	panelSpacing := 1
+	if OverlappingEdges {
+		panelSpacing = 0
+	}
+
+	viewDimensions := map[string]dimensions{}
+	var sideWidth, sideHeight int
+	switch {
+	case sideSize == 0:
+		viewDimensions["main"] = dimensions{0, 0, width - 1, bottom}
+	case sideSize == size:
+		viewDimensions["main"] = hidden
+		sideWidth, sideHeight = width, height-1
+	case portrait:
+		viewDimensions["main"] = dimensions{0, sideSize, width - 1, bottom}
+		sideWidth, sideHeight = width, sideSize
+	default:
+		viewDimensions["main"] = dimensions{sideSize + panelSpacing, 0, width - 1, bottom}
+		sideWidth, sideHeight = sideSize+1, height-1
+	}
+
+	viewDimensions["commandLog"] = hidden
+	if main := viewDimensions["main"]; gui.State.ShowCommandLog && main != hidden {
+		// the command log takes the bottom of the main panel, if it's tall enough
+		mainHeight := main.y1 - main.y0 + 1
+		if mainHeight >= minMainHeightForCommandLog {
+			logHeight := utils.Max(mainHeight/3, 5)
+			viewDimensions["main"] = dimensions{main.x0, main.y0, main.x1, main.y1 - logHeight}
+			viewDimensions["commandLog"] = dimensions{main.x0, main.y1 - logHeight + 1, main.x1, main.y1}
+		}
+	}
+
+	heights := sidePanelHeights(sideHeight, currentCyclebleView)
+	if sideSize == 0 || gui.State.ScreenMode == screenModeFull {
+		// only the focused side panel is shown, if any
+		for _, viewName := range cyclableViews {
+			heights[viewName] = 0
+		}
+		if sideSize > 0 {
+			heights[currentCyclebleView] = sideHeight
+		}
+	}
+
+	y := 0
+	for _, viewName := range cyclableViews {
+		if heights[viewName] <= 0 {
+			viewDimensions[viewName] = hidden
+			continue
+		}
+		viewDimensions[viewName] = dimensions{0, y, sideWidth - 1, y + heights[viewName] - 1}
+		y += heights[viewName]
+	}
+	// the commit files panel takes the place of the commits panel
+	viewDimensions["commitFiles"] = viewDimensions["commits"]
+
+	return viewDimensions
+}
+
+// sidePanelHeights shares out the given height between the side panels. If
+// there's not much of it, every panel but the focused one is squashed down
+func sidePanelHeights(height int, currentCyclebleView string) map[string]int {
+	if height >= 27 {
+		usableSpace := height - 6
+		extraSpace := usableSpace - (usableSpace/3)*3
+		return map[string]int{
+			"status":   3,
+			"files":    (usableSpace / 3) + extraSpace,
+			"branches": usableSpace / 3,
+			"commits":  usableSpace / 3,
+			"stash":    3,
+		}
+	}
+
+	defaultHeight := 3
+	if height < 20 {
+		defaultHeight = 1
+	}
+	heights := map[string]int{}
+	for _, viewName := range cyclableViews {
+		heights[viewName] = defaultHeight
+	}
+	heights[currentCyclebleView] = height - defaultHeight*4
+	return heights
+}
+
+func (gui *Gui) setViewFromDimensions(viewName string, viewDimensions map[string]dimensions) (*gocui.View, error) {
+	d := viewDimensions[viewName]
+	return gui.g.SetView(viewName, d.x0, d.y0, d.x1, d.y1, 0)
+}
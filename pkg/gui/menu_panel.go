@@ -16,35 +16,34 @@ func (gui *Gui) handleMenuSelect(g *gocui.Gui, v *gocui.View) error {
 
 func (gui *Gui) handleMenuNextLine(g *gocui.Gui, v *gocui.View) error {
 	panelState := gui.State.Panels.Menu
-	gui.changeSelectedLine(&panelState.SelectedLine, v.LinesHeight(), false)
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, gui.State.MenuItemCount, false)
 
 	return gui.handleMenuSelect(g, v)
 }
 
 func (gui *Gui) handleMenuPrevLine(g *gocui.Gui, v *gocui.View) error {
 	panelState := gui.State.Panels.Menu
-	gui.changeSelectedLine(&panelState.SelectedLine, v.LinesHeight(), true)
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, gui.State.MenuItemCount, true)
 
 	return gui.handleMenuSelect(g, v)
 }
 
+// the actions that run the selected menu item
+var menuSelectKeybindings = []string{"universal.select", "universal.confirm"}
+
 // specific functions
 
 func (gui *Gui) renderMenuOptions() error {
 	optionsMap := map[string]string{
-		"esc/q": gui.Tr.SLocalize("close"),
-		"↑ ↓":   gui.Tr.SLocalize("navigate"),
-		"space": gui.Tr.SLocalize("execute"),
+		fmt.Sprintf("%s/%s", gui.getKeyDisplay("universal.return"), gui.getKeyDisplay("universal.quit")):       gui.Tr.SLocalize("close"),
+		fmt.Sprintf("%s %s", gui.getKeyDisplay("universal.prevItem"), gui.getKeyDisplay("universal.nextItem")): gui.Tr.SLocalize("navigate"),
+		gui.getKeyDisplay("universal.select"): gui.Tr.SLocalize("execute"),
 	}
 	return gui.renderOptionsMap(optionsMap)
 }
 
 func (gui *Gui) handleMenuClose(g *gocui.Gui, v *gocui.View) error {
-	for _, key := range []gocui.Key{gocui.KeySpace, gocui.KeyEnter} {
-		if err := g.DeleteKeybinding("menu", key, gocui.ModNone); err != nil {
-			return err
-		}
-	}
+	gui.deleteNamedKeybindings("menu", menuSelectKeybindings)
 	err := g.DeleteView("menu")
 	if err != nil {
 		return err
@@ -64,8 +63,10 @@ func (gui *Gui) createMenu(title string, items interface{}, itemCount int, handl
 	menuView, _ := gui.g.SetView("menu", x0, y0, x1, y1, 0)
 	menuView.Title = title
 	menuView.FgColor = theme.GocuiDefaultTextColor
-	menuView.Clear()
-	fmt.Fprint(menuView, list)
+	// a new menu starts out unfiltered
+	delete(gui.State.Filters, "menu")
+	menuView.Subtitle = ""
+	gui.renderListContent(menuView, list)
 	gui.State.Panels.Menu.SelectedLine = 0
 
 	wrappedHandlePress := func(g *gocui.Gui, v *gocui.View) error {
@@ -82,12 +83,29 @@ func (gui *Gui) createMenu(title string, items interface{}, itemCount int, handl
 		return gui.returnFocus(gui.g, menuView)
 	}
 
-	for _, key := range []gocui.Key{gocui.KeySpace, gocui.KeyEnter} {
-		_ = gui.g.DeleteKeybinding("menu", key, gocui.ModNone)
-
-		if err := gui.g.SetKeybinding("menu", key, gocui.ModNone, wrappedHandlePress); err != nil {
-			return err
+	// clicking an entry selects it, and clicking the selected entry runs it
+	handleMenuClick := func(g *gocui.Gui, v *gocui.View) error {
+		if gui.currentViewName() != "menu" {
+			return nil
+		}
+		index := gui.clickedItem(v)
+		if index == -1 {
+			return nil
 		}
+		if index == gui.State.Panels.Menu.SelectedLine {
+			return wrappedHandlePress(g, v)
+		}
+		gui.State.Panels.Menu.SelectedLine = index
+		return gui.handleMenuSelect(g, v)
+	}
+
+	gui.deleteNamedKeybindings("menu", menuSelectKeybindings)
+	if err := gui.setNamedKeybindings("menu", menuSelectKeybindings, wrappedHandlePress); err != nil {
+		return err
+	}
+	gui.deleteKeybindings("menu", gocui.MouseLeft)
+	if err := gui.g.SetKeybinding("menu", gocui.MouseLeft, gocui.ModNone, handleMenuClick); err != nil {
+		return err
 	}
 
 	gui.g.Update(func(g *gocui.Gui) error {
@@ -5,15 +5,16 @@ package gui
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io/ioutil"
 	"math"
 	"os"
 	"strings"
 
-	"github.com/fatih/color"
 	"github.com/golang-collections/collections/stack"
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/syntax"
 	"github.com/jesseduffield/lazygit/pkg/theme"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 )
@@ -44,25 +45,36 @@ func (gui *Gui) shouldHighlightLine(index int, conflict commands.Conflict, top b
 	return (index >= conflict.Start && index <= conflict.Middle && top) || (index >= conflict.Middle && index <= conflict.End && !top)
 }
 
-func (gui *Gui) coloredConflictFile(content string, conflicts []commands.Conflict, conflictIndex int, conflictTop, hasFocus bool) (string, error) {
+// coloredConflictFile colors the conflicts in a file. If we know the file's
+// language, the code around the selected conflict gets syntax highlighting
+// too, the rest of the file being off screen
+func (gui *Gui) coloredConflictFile(content string, conflicts []commands.Conflict, conflictIndex int, conflictTop, hasFocus bool, highlighter *syntax.Highlighter) (string, error) {
 	if len(conflicts) == 0 {
 		return content, nil
 	}
+	_, height := gui.getMainView().Size()
+	highlightFrom := conflicts[conflictIndex].Start - height
+	highlightTo := conflicts[conflictIndex].End + height
+
 	conflict, remainingConflicts := gui.shiftConflict(conflicts)
 	var outputBuffer bytes.Buffer
 	for i, line := range utils.SplitLines(content) {
-		colourAttr := theme.DefaultTextColor
+		colour := theme.NewTextStyle(theme.DefaultTextColor)
 		if i == conflict.Start || i == conflict.Middle || i == conflict.End {
-			colourAttr = color.FgRed
+			colour = theme.ConflictMarkerColor
 		}
-		colour := color.New(colourAttr)
 		if hasFocus && conflictIndex < len(conflicts) && conflicts[conflictIndex] == conflict && gui.shouldHighlightLine(i, conflict, conflictTop) {
-			colour.Add(color.Bold)
+			colour = colour.Add(theme.SelectedConflictColor)
 		}
+		isMarker := i == conflict.Start || i == conflict.Middle || i == conflict.End
 		if i == conflict.End && len(remainingConflicts) > 0 {
 			conflict, remainingConflicts = gui.shiftConflict(remainingConflicts)
 		}
-		outputBuffer.WriteString(utils.ColoredStringDirect(line, colour) + "\n")
+		if highlighter != nil && !isMarker && i >= highlightFrom && i <= highlightTo {
+			outputBuffer.WriteString(highlighter.HighlightLine(line, colour) + "\n")
+			continue
+		}
+		outputBuffer.WriteString(colour.Sprint(line) + "\n")
 	}
 	return outputBuffer.String(), nil
 }
@@ -93,6 +105,32 @@ func (gui *Gui) handleSelectPrevConflict(g *gocui.Gui, v *gocui.View) error {
 	return gui.refreshMergePanel()
 }
 
+// handleMergeClick selects the side of the conflict that was clicked on, or
+// picks it if it was already selected. Clicking the ======= line between the
+// two sides just selects the conflict
+func (gui *Gui) handleMergeClick(g *gocui.Gui, v *gocui.View) error {
+	panelState := gui.State.Panels.Merging
+	_, cy := v.Cursor()
+	_, oy := v.Origin()
+	line := cy + oy
+
+	for i, conflict := range panelState.Conflicts {
+		if line < conflict.Start || line > conflict.End {
+			continue
+		}
+		top := line < conflict.Middle
+		if line == conflict.Middle {
+			top = panelState.ConflictTop
+		} else if i == panelState.ConflictIndex && top == panelState.ConflictTop {
+			return gui.handlePickHunk(g, v)
+		}
+		panelState.ConflictIndex = i
+		panelState.ConflictTop = top
+		return gui.refreshMergePanel()
+	}
+	return nil
+}
+
 func (gui *Gui) isIndexToDelete(i int, conflict commands.Conflict, pick string) bool {
 	return i == conflict.Middle ||
 		i == conflict.Start ||
@@ -206,8 +244,15 @@ func (gui *Gui) refreshMergePanel() error {
 		panelState.ConflictIndex = len(panelState.Conflicts) - 1
 	}
 
+	var highlighter *syntax.Highlighter
+	if file, err := gui.getSelectedFile(gui.g); err == nil && len(theme.SyntaxColors) > 0 {
+		if language := syntax.LanguageForFile(file.Name); language != nil {
+			highlighter = syntax.NewHighlighter(language)
+		}
+	}
+
 	hasFocus := gui.currentViewName() == "main"
-	content, err := gui.coloredConflictFile(cat, panelState.Conflicts, panelState.ConflictIndex, panelState.ConflictTop, hasFocus)
+	content, err := gui.coloredConflictFile(cat, panelState.Conflicts, panelState.ConflictIndex, panelState.ConflictTop, hasFocus, highlighter)
 	if err != nil {
 		return err
 	}
@@ -243,11 +288,11 @@ func (gui *Gui) scrollToConflict(g *gocui.Gui) error {
 
 func (gui *Gui) renderMergeOptions() error {
 	return gui.renderOptionsMap(map[string]string{
-		"↑ ↓":   gui.Tr.SLocalize("selectHunk"),
-		"← →":   gui.Tr.SLocalize("navigateConflicts"),
-		"space": gui.Tr.SLocalize("pickHunk"),
-		"b":     gui.Tr.SLocalize("pickBothHunks"),
-		"z":     gui.Tr.SLocalize("undo"),
+		fmt.Sprintf("%s %s", gui.getKeyDisplay("universal.prevItem"), gui.getKeyDisplay("universal.nextItem")):   gui.Tr.SLocalize("selectHunk"),
+		fmt.Sprintf("%s %s", gui.getKeyDisplay("universal.prevBlock"), gui.getKeyDisplay("universal.nextBlock")): gui.Tr.SLocalize("navigateConflicts"),
+		gui.getKeyDisplay("main.pickHunk"):      gui.Tr.SLocalize("pickHunk"),
+		gui.getKeyDisplay("main.pickBothHunks"): gui.Tr.SLocalize("pickBothHunks"),
+		gui.getKeyDisplay("main.undo"):          gui.Tr.SLocalize("undo"),
 	})
 }
 
@@ -7,6 +7,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/config"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
@@ -61,6 +62,15 @@ func (gui *Gui) updateRecentRepoList() error {
 	return gui.Config.SaveAppState()
 }
 
+// getRepoState returns the persisted state of the repo we're currently in
+func (gui *Gui) getRepoState() *config.RepoState {
+	currentRepo, err := os.Getwd()
+	if err != nil {
+		gui.Log.Error(err)
+	}
+	return gui.Config.GetAppState().GetRepoState(currentRepo)
+}
+
 // newRecentReposList returns a new repo list with a new entry but only when it doesn't exist yet
 func newRecentReposList(recentRepos []string, currentRepo string) (bool, []string) {
 	isNew := true
@@ -0,0 +1,356 @@
+package gui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/theme"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// searchState is the search that's highlighted in one of the views. ViewName
+// is the view being searched, and KeyView is the side panel the search was
+// started from, which is where n and N are bound while the search lasts. The
+// two are the same unless we're searching the main view
+type searchState struct {
+	ViewName string
+	KeyView  string
+	Query    string
+	// the lines of the view's content that have a match on them
+	Matches []int
+	// the position in Matches of the match we last jumped to, or -1
+	Index int
+	// the view's title without the number of matches
+	BaseTitle string
+}
+
+// selectedLinePointer returns the selected line of a list panel that can be
+// searched, or nil for any other view
+func (gui *Gui) selectedLinePointer(viewName string) *int {
+	switch viewName {
+	case "files":
+		return &gui.State.Panels.Files.SelectedLine
+	case "branches":
+		return &gui.State.Panels.Branches.SelectedLine
+	case "commits":
+		return &gui.State.Panels.Commits.SelectedLine
+	case "commitFiles":
+		return &gui.State.Panels.CommitFiles.SelectedLine
+	case "stash":
+		return &gui.State.Panels.Stash.SelectedLine
+	case "menu":
+		return &gui.State.Panels.Menu.SelectedLine
+	}
+	return nil
+}
+
+func (gui *Gui) handleStartSearch(g *gocui.Gui, v *gocui.View) error {
+	if gui.popupPanelFocused() || gui.selectedLinePointer(v.Name()) == nil {
+		return nil
+	}
+	return gui.createSearchPrompt(v, v.Name())
+}
+
+// handleStartSearchMain searches whatever the main view is showing for the
+// side panel that has focus
+func (gui *Gui) handleStartSearchMain(g *gocui.Gui, v *gocui.View) error {
+	if gui.popupPanelFocused() || v.Name() == "main" || gui.State.Contexts["main"] != "normal" {
+		return nil
+	}
+	return gui.createSearchPrompt(v, "main")
+}
+
+func (gui *Gui) createSearchPrompt(v *gocui.View, viewName string) error {
+	initialContent := ""
+	if gui.State.Search != nil && gui.State.Search.ViewName == viewName {
+		initialContent = gui.State.Search.Query
+	}
+	return gui.createPromptPanel(gui.g, v, gui.Tr.SLocalize("SearchPrompt"), initialContent, func(g *gocui.Gui, promptView *gocui.View) error {
+		if err := gui.clearSearch(); err != nil {
+			return err
+		}
+		query := gui.trimmedContent(promptView)
+		if query == "" {
+			return nil
+		}
+		return gui.startSearch(v, viewName, query)
+	})
+}
+
+func (gui *Gui) startSearch(keyView *gocui.View, viewName string, query string) error {
+	v, err := gui.g.View(viewName)
+	if err != nil {
+		return nil
+	}
+	gui.State.Search = &searchState{
+		ViewName:  viewName,
+		KeyView:   keyView.Name(),
+		Query:     query,
+		Index:     -1,
+		BaseTitle: v.Title,
+	}
+	if err := gui.bindSearchKeys(keyView.Name()); err != nil {
+		return err
+	}
+
+	if viewName == "main" {
+		// the content is highlighted as it's rendered, so we just render it
+		// again. We leave jumping to the first match to n, seeing as going
+		// back to the side panel shows its diff again from the top
+		return gui.rerenderMain(v)
+	}
+	gui.redrawList(v)
+	return gui.goToMatch(1)
+}
+
+// clearSearch takes the highlighting off the searched view and gives n and N
+// back to whatever they were bound to before
+func (gui *Gui) clearSearch() error {
+	search := gui.State.Search
+	if search == nil {
+		return nil
+	}
+	gui.State.Search = nil
+	if err := gui.unbindSearchKeys(search.KeyView); err != nil {
+		return err
+	}
+
+	v, err := gui.g.View(search.ViewName)
+	if err != nil {
+		return nil
+	}
+	v.Title = search.BaseTitle
+	if search.ViewName == "main" {
+		return gui.rerenderMain(v)
+	}
+	gui.redrawList(v)
+	return nil
+}
+
+func (gui *Gui) searchKeys() ([]interface{}, error) {
+	keys := []interface{}{}
+	for _, name := range []string{"universal.nextMatch", "universal.prevMatch"} {
+		key, err := gui.getKey(name)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// bindSearchKeys binds n and N in the given view, taking precedence over
+// anything else they do there, like creating a branch
+func (gui *Gui) bindSearchKeys(viewName string) error {
+	keys, err := gui.searchKeys()
+	if err != nil {
+		return err
+	}
+	handlers := []func(*gocui.Gui, *gocui.View) error{gui.handleNextMatch, gui.handlePrevMatch}
+	for i, key := range keys {
+		gui.deleteKeybindings(viewName, key)
+		if err := gui.g.SetKeybinding(viewName, key, gocui.ModNone, handlers[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gui *Gui) unbindSearchKeys(viewName string) error {
+	keys, err := gui.searchKeys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		gui.deleteKeybindings(viewName, key)
+		for _, binding := range gui.GetInitialKeybindings() {
+			if binding.ViewName == viewName && binding.Key == key {
+				if err := gui.g.SetKeybinding(viewName, binding.Key, binding.Modifier, binding.Handler); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// deleteKeybindings deletes every binding of a key in a view, where gocui only
+// deletes the first
+func (gui *Gui) deleteKeybindings(viewName string, key interface{}) {
+	for gui.g.DeleteKeybinding(viewName, key, gocui.ModNone) == nil {
+	}
+}
+
+// rerenderMain renders the main view's content again, e.g. to add or remove
+// search highlighting
+func (gui *Gui) rerenderMain(v *gocui.View) error {
+	gui.setMainLines(v, gui.State.MainLines)
+	return nil
+}
+
+// highlightSearchMatches highlights the matches of the search in the lines of
+// a view, if it's the view being searched, keeping track of which lines they
+// were on and updating the title to match
+func (gui *Gui) highlightSearchMatches(v *gocui.View, lines []string) []string {
+	gui.updateSearchMatches(v, lines)
+	return gui.styleSearchMatches(v, lines)
+}
+
+// updateSearchMatches finds the lines of a view's content that the search
+// matches, if it's the view being searched, and updates the title to match.
+// The main view only highlights the matches in the lines it writes, but needs
+// to know where all of them are
+func (gui *Gui) updateSearchMatches(v *gocui.View, lines []string) {
+	search := gui.State.Search
+	if search == nil || search.ViewName != v.Name() {
+		return
+	}
+
+	matches := []int{}
+	for i, line := range lines {
+		if _, count := utils.StyleMatches(line, search.Query, ""); count > 0 {
+			matches = append(matches, i)
+		}
+	}
+
+	// if the content's changed, the match we were on is gone
+	if !reflect.DeepEqual(matches, search.Matches) {
+		search.Index = -1
+	}
+	search.Matches = matches
+	v.Title = gui.searchTitle()
+}
+
+// styleSearchMatches highlights the matches of the search in the lines of a
+// view, if it's the view being searched
+func (gui *Gui) styleSearchMatches(v *gocui.View, lines []string) []string {
+	search := gui.State.Search
+	if search == nil || search.ViewName != v.Name() {
+		return lines
+	}
+
+	sequence := theme.SearchMatchColor.Sequence()
+	highlighted := make([]string, len(lines))
+	for i, line := range lines {
+		highlighted[i], _ = utils.StyleMatches(line, search.Query, sequence)
+	}
+	return highlighted
+}
+
+// searchTitle is the title of the searched view, followed by how many matches
+// there are and which of them we're on
+func (gui *Gui) searchTitle() string {
+	search := gui.State.Search
+	teml := Teml{
+		"query": search.Query,
+		"count": len(search.Matches),
+		"index": search.Index + 1,
+	}
+	var status string
+	switch {
+	case len(search.Matches) == 0:
+		status = gui.Tr.TemplateLocalize("SearchNoMatches", teml)
+	case search.Index == -1:
+		status = gui.Tr.TemplateLocalize("SearchMatchCount", teml)
+	default:
+		status = gui.Tr.TemplateLocalize("SearchMatchPosition", teml)
+	}
+	if search.BaseTitle == "" {
+		return status
+	}
+	return fmt.Sprintf("%s (%s)", search.BaseTitle, status)
+}
+
+func (gui *Gui) handleNextMatch(g *gocui.Gui, v *gocui.View) error {
+	return gui.goToMatch(1)
+}
+
+func (gui *Gui) handlePrevMatch(g *gocui.Gui, v *gocui.View) error {
+	return gui.goToMatch(-1)
+}
+
+// goToMatch moves to the next match in the given direction, wrapping around
+// at either end. The first jump goes to the nearest match from where we are
+func (gui *Gui) goToMatch(step int) error {
+	search := gui.State.Search
+	if search == nil || len(search.Matches) == 0 {
+		return nil
+	}
+	v, err := gui.g.View(search.ViewName)
+	if err != nil {
+		return nil
+	}
+
+	count := len(search.Matches)
+	if search.Index == -1 {
+		search.Index = nearestMatch(search.Matches, gui.searchPosition(v), step)
+	} else {
+		search.Index = (search.Index + step + count) % count
+	}
+	v.Title = gui.searchTitle()
+	line := search.Matches[search.Index]
+
+	if search.ViewName == "main" {
+		gui.scrollMainToLine(v, line)
+		return nil
+	}
+
+	*gui.selectedLinePointer(search.ViewName) = gui.filteredIndex(search.ViewName, line)
+	return gui.newLineFocused(gui.g, v)
+}
+
+// searchPosition is the line of the searched view that we're on: the selected
+// line of a list, or the top line on screen of the main view
+func (gui *Gui) searchPosition(v *gocui.View) int {
+	if selectedLine := gui.selectedLinePointer(v.Name()); selectedLine != nil {
+		line, _ := gui.filteredLine(v.Name(), *selectedLine, 0)
+		return line
+	}
+	line, _ := gui.mainTopLine(v)
+	return line
+}
+
+// nearestMatch returns the position of the first match at or after the given
+// line when going forwards, or at or before it when going backwards, wrapping
+// around if there isn't one
+func nearestMatch(matches []int, line int, step int) int {
+	if step > 0 {
+		for i, match := range matches {
+			if match >= line {
+				return i
+			}
+		}
+		return 0
+	}
+	for i := len(matches) - 1; i >= 0; i-- {
+		if matches[i] <= line {
+			return i
+		}
+	}
+	return len(matches) - 1
+}
+
+// wrappedRow returns the row of the view that the given line of its content
+// starts on, which is further down than the line's index when lines before it
+// wrap
+func wrappedRow(v *gocui.View, lines []string, index int) int {
+	if !v.Wrap {
+		return index
+	}
+	row := 0
+	for _, line := range lines[:index] {
+		row += lineRows(v, line)
+	}
+	return row
+}
+
+// lineRows returns how many rows of the view a line takes up once wrapped
+func lineRows(v *gocui.View, line string) int {
+	width, _ := v.Size()
+	if !v.Wrap || width <= 0 {
+		return 1
+	}
+	return utils.Max(1, (len([]rune(strings.TrimRight(utils.Decolorise(line), "\n")))+width-1)/width)
+}
@@ -1,8 +1,11 @@
 package gui
 
 import (
+	"strings"
+
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/git"
+	"github.com/jesseduffield/lazygit/pkg/theme"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
@@ -19,9 +22,10 @@ func (gui *Gui) refreshStagingPanel() error {
 		return gui.handleStagingEscape(gui.g, nil)
 	}
 
-	// note for custom diffs, we'll need to send a flag here saying not to use the custom diff
-	diff := gui.GitCommand.Diff(file, true)
-	colorDiff := gui.GitCommand.Diff(file, false)
+	// we select lines by their position in the plain diff, so the coloured one
+	// has to skip the user's pager for those positions to line up
+	diff := gui.GitCommand.Diff(file, true, true)
+	colorDiff := gui.GitCommand.Diff(file, false, true)
 
 	if len(diff) < 2 {
 		return gui.handleStagingEscape(gui.g, nil)
@@ -55,6 +59,7 @@ func (gui *Gui) refreshStagingPanel() error {
 		HunkStarts:     hunkStarts,
 		SelectedLine:   selectedLine,
 		Diff:           diff,
+		ColorDiff:      colorDiff,
 	}
 
 	if len(stageableLines) == 0 {
@@ -70,7 +75,7 @@ func (gui *Gui) refreshStagingPanel() error {
 	mainView.Wrap = false
 
 	gui.g.Update(func(*gocui.Gui) error {
-		return gui.setViewContent(gui.g, gui.getMainView(), colorDiff)
+		return gui.renderStagingDiff()
 	})
 
 	return nil
@@ -99,6 +104,9 @@ func (gui *Gui) handleStagingNextHunk(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) handleCycleHunk(prev bool) error {
+	if err := gui.clearStagingRange(); err != nil {
+		return err
+	}
 	state := gui.State.Panels.Staging
 	lineNumbers := state.StageableLines
 	currentLine := lineNumbers[state.SelectedLine]
@@ -124,6 +132,9 @@ func (gui *Gui) handleCycleHunk(prev bool) error {
 }
 
 func (gui *Gui) handleCycleLine(prev bool) error {
+	if err := gui.clearStagingRange(); err != nil {
+		return err
+	}
 	state := gui.State.Panels.Staging
 	lineNumbers := state.StageableLines
 	currentLine := lineNumbers[state.SelectedLine]
@@ -195,6 +206,9 @@ func (gui *Gui) handleStageLineOrHunk(hunk bool) error {
 	var patch string
 	if hunk {
 		patch, err = p.ModifyPatchForHunk(state.Diff, state.HunkStarts, currentLine)
+	} else if state.SelectingRange {
+		firstLine, lastLine := state.selectedRange()
+		patch, err = p.ModifyPatchForRange(state.Diff, firstLine, lastLine)
 	} else {
 		patch, err = p.ModifyPatchForLine(state.Diff, currentLine)
 	}
@@ -220,3 +234,101 @@ func (gui *Gui) handleStageLineOrHunk(hunk bool) error {
 	}
 	return nil
 }
+
+// renderStagingDiff shows the diff being staged, with any range of lines being
+// selected with the mouse picked out in the selected range color
+func (gui *Gui) renderStagingDiff() error {
+	state := gui.State.Panels.Staging
+	if state == nil {
+		return nil
+	}
+	colorDiff := state.ColorDiff
+	if state.SelectingRange && !theme.SelectedRangeColor.IsEmpty() {
+		firstLine, lastLine := state.selectedRange()
+		sequence := theme.SelectedRangeColor.Sequence()
+		lines := strings.Split(colorDiff, "\n")
+		for i := firstLine; i <= lastLine && i < len(lines); i++ {
+			// the line's own styling ends with a reset, after which we need to
+			// bring the range color back
+			line := resetSequenceRegexp.ReplaceAllStringFunc(lines[i], func(reset string) string {
+				return reset + sequence
+			})
+			lines[i] = sequence + line + "\x1b[0m"
+		}
+		colorDiff = strings.Join(lines, "\n")
+	}
+	return gui.setViewContent(gui.g, gui.getMainView(), colorDiff)
+}
+
+// selectedRange returns the first and last line of the diff in the range being
+// selected with the mouse
+func (s *stagingPanelState) selectedRange() (int, int) {
+	first, last := s.StageableLines[s.RangeStart], s.StageableLines[s.SelectedLine]
+	if first > last {
+		return last, first
+	}
+	return first, last
+}
+
+func (gui *Gui) clearStagingRange() error {
+	state := gui.State.Panels.Staging
+	if !state.SelectingRange {
+		return nil
+	}
+	state.SelectingRange = false
+	return gui.renderStagingDiff()
+}
+
+// handleStagingClick selects the stageable line nearest to where the diff was
+// clicked on
+func (gui *Gui) handleStagingClick(g *gocui.Gui, v *gocui.View) error {
+	if err := gui.clearStagingRange(); err != nil {
+		return err
+	}
+	return gui.selectClickedStagingLine(v)
+}
+
+// handleStagingDrag selects the stageable lines from the one the mouse was
+// pressed on to the one it's been dragged to
+func (gui *Gui) handleStagingDrag(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.Staging
+	if !state.SelectingRange {
+		state.SelectingRange = true
+		state.RangeStart = state.SelectedLine
+	}
+	if err := gui.selectClickedStagingLine(v); err != nil {
+		return err
+	}
+	return gui.renderStagingDiff()
+}
+
+func (gui *Gui) selectClickedStagingLine(v *gocui.View) error {
+	state := gui.State.Panels.Staging
+	_, cy := v.Cursor()
+	_, oy := v.Origin()
+	clickedLine := cy + oy
+
+	nearest := 0
+	for i, lineNumber := range state.StageableLines {
+		if distance(lineNumber, clickedLine) < distance(state.StageableLines[nearest], clickedLine) {
+			nearest = i
+		}
+	}
+	state.SelectedLine = nearest
+
+	// we leave the diff where it is rather than scrolling to the line's hunk
+	// like the keyboard does, so that it doesn't move out from under the mouse
+	_, height := v.Size()
+	lineNumber := state.StageableLines[nearest]
+	if lineNumber < oy || lineNumber >= oy+height {
+		return gui.focusLineAndHunk()
+	}
+	return v.SetCursor(0, lineNumber-oy)
+}
+
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
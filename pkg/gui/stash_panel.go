@@ -1,8 +1,6 @@
 package gui
 
 import (
-	"fmt"
-
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/commands"
 	"github.com/jesseduffield/lazygit/pkg/utils"
@@ -34,34 +32,36 @@ func (gui *Gui) handleStashEntrySelect(g *gocui.Gui, v *gocui.View) error {
 	if err := gui.focusPoint(0, gui.State.Panels.Stash.SelectedLine, len(gui.State.StashEntries), v); err != nil {
 		return err
 	}
-	go func() {
-		// doing this asynchronously cos it can take time
-		diff, _ := gui.GitCommand.GetStashEntryDiff(stashEntry.Index)
-		_ = gui.renderString(g, "main", diff)
-	}()
+	gui.showDiff(
+		func() []string { return []string{gui.GitCommand.StashEntryDiffCommand(stashEntry.Index)} },
+		func() (string, error) { return gui.GitCommand.GetStashEntryDiff(stashEntry.Index) },
+		false,
+	)
 	return nil
 }
 
 func (gui *Gui) refreshStashEntries(g *gocui.Gui) error {
-	g.Update(func(g *gocui.Gui) error {
-		gui.State.StashEntries = gui.GitCommand.GetStashEntries()
-
-		gui.refreshSelectedLine(&gui.State.Panels.Stash.SelectedLine, len(gui.State.StashEntries))
-
-		isFocused := gui.g.CurrentView().Name() == "stash"
-		list, err := utils.RenderList(gui.State.StashEntries, isFocused)
-		if err != nil {
-			return err
-		}
-
-		v := gui.getStashView()
-		v.Clear()
-		fmt.Fprint(v, list)
-
-		if err := gui.resetOrigin(v); err != nil {
-			return err
-		}
-		return nil
+	gui.loadCachedInBackground("stash", gui.GitCommand.RefsFingerprint, func() (func(*gocui.Gui) error, error) {
+		stashEntries := gui.GitCommand.GetStashEntries()
+		return func(g *gocui.Gui) error {
+			gui.State.StashEntries = stashEntries
+
+			gui.refreshSelectedLine(&gui.State.Panels.Stash.SelectedLine, len(gui.State.StashEntries))
+
+			isFocused := gui.g.CurrentView().Name() == "stash"
+			list, err := utils.RenderList(gui.State.StashEntries, isFocused)
+			if err != nil {
+				return err
+			}
+
+			v := gui.getStashView()
+			gui.renderListContent(v, list)
+
+			if err := gui.resetOrigin(v); err != nil {
+				return err
+			}
+			return nil
+		}, nil
 	})
 	return nil
 }
@@ -72,7 +72,7 @@ func (gui *Gui) handleStashNextLine(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	panelState := gui.State.Panels.Stash
-	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.StashEntries), false)
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, len(gui.State.StashEntries), false)
 
 	if err := gui.resetOrigin(gui.getMainView()); err != nil {
 		return err
@@ -86,7 +86,7 @@ func (gui *Gui) handleStashPrevLine(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	panelState := gui.State.Panels.Stash
-	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.StashEntries), true)
+	gui.changeSelectedLine(v.Name(), &panelState.SelectedLine, len(gui.State.StashEntries), true)
 
 	if err := gui.resetOrigin(gui.getMainView()); err != nil {
 		return err
@@ -134,7 +134,7 @@ func (gui *Gui) handleStashSave(stashFunc func(message string) error) error {
 	if len(gui.trackedFiles()) == 0 && len(gui.stagedFiles()) == 0 {
 		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NoTrackedStagedFilesStash"))
 	}
-	return gui.createPromptPanel(gui.g, gui.getFilesView(), gui.Tr.SLocalize("StashChanges"), func(g *gocui.Gui, v *gocui.View) error {
+	return gui.createPromptPanel(gui.g, gui.getFilesView(), gui.Tr.SLocalize("StashChanges"), "", func(g *gocui.Gui, v *gocui.View) error {
 		if err := stashFunc(gui.trimmedContent(v)); err != nil {
 			gui.createErrorPanel(g, err.Error())
 		}
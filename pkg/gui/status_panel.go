@@ -14,34 +14,39 @@ func (gui *Gui) refreshStatus(g *gocui.Gui) error {
 	if err != nil {
 		panic(err)
 	}
-	// for some reason if this isn't wrapped in an update the clear seems to
-	// be applied after the other things or something like that; the panel's
-	// contents end up cleared
-	g.Update(func(*gocui.Gui) error {
-		v.Clear()
+	gui.loadInBackground("status", func() (func(*gocui.Gui) error, error) {
 		pushables, pullables := gui.GitCommand.GetCurrentBranchUpstreamDifferenceCount()
-		fmt.Fprint(v, "↑"+pushables+"↓"+pullables)
-		branches := gui.State.Branches
-		if err := gui.updateWorkTreeState(); err != nil {
-			return err
+		workingTreeState, err := gui.getWorkTreeState()
+		if err != nil {
+			return nil, err
 		}
-		if gui.State.WorkingTreeState != "normal" {
-			fmt.Fprint(v, utils.ColoredString(fmt.Sprintf(" (%s)", gui.State.WorkingTreeState), color.FgYellow))
-		}
-
-		if len(branches) == 0 {
-			return nil
-		}
-		branch := branches[0]
-		name := utils.ColoredString(branch.Name, branch.GetColor())
-		repo := utils.GetCurrentRepoName()
-		fmt.Fprint(v, " "+repo+" → "+name)
-		return nil
+		return func(*gocui.Gui) error {
+			return gui.renderStatus(v, pushables, pullables, workingTreeState)
+		}, nil
 	})
 
 	return nil
 }
 
+func (gui *Gui) renderStatus(v *gocui.View, pushables, pullables, workingTreeState string) error {
+	v.Clear()
+	fmt.Fprint(v, "↑"+pushables+"↓"+pullables)
+	branches := gui.State.Branches
+	gui.State.WorkingTreeState = workingTreeState
+	if gui.State.WorkingTreeState != "normal" {
+		fmt.Fprint(v, utils.ColoredString(fmt.Sprintf(" (%s)", gui.State.WorkingTreeState), color.FgYellow))
+	}
+
+	if len(branches) == 0 {
+		return nil
+	}
+	branch := branches[0]
+	name := branch.GetColor().Sprint(branch.Name)
+	repo := utils.GetCurrentRepoName()
+	fmt.Fprint(v, " "+repo+" → "+name)
+	return nil
+}
+
 func (gui *Gui) handleCheckForUpdate(g *gocui.Gui, v *gocui.View) error {
 	gui.Updater.CheckForNewUpdate(gui.onUserUpdateCheckFinish, true)
 	return gui.createLoaderPanel(gui.g, v, gui.Tr.SLocalize("CheckingForUpdates"))
@@ -92,23 +97,22 @@ func lazygitTitle() string {
                |___/ |___/       `
 }
 
-func (gui *Gui) updateWorkTreeState() error {
+// getWorkTreeState tells us whether we're in the middle of a merge or rebase.
+// It doesn't touch the gui's state, so it can be called from any goroutine
+func (gui *Gui) getWorkTreeState() (string, error) {
 	merging, err := gui.GitCommand.IsInMergeState()
 	if err != nil {
-		return err
+		return "", err
 	}
 	if merging {
-		gui.State.WorkingTreeState = "merging"
-		return nil
+		return "merging", nil
 	}
 	rebaseMode, err := gui.GitCommand.RebaseMode()
 	if err != nil {
-		return err
+		return "", err
 	}
 	if rebaseMode != "" {
-		gui.State.WorkingTreeState = "rebasing"
-		return nil
+		return "rebasing", nil
 	}
-	gui.State.WorkingTreeState = "normal"
-	return nil
+	return "normal", nil
 }
@@ -2,23 +2,27 @@ package gui
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/git"
+	"github.com/jesseduffield/lazygit/pkg/theme"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 	"github.com/spkg/bom"
 )
 
 var cyclableViews = []string{"status", "files", "branches", "commits", "stash"}
 
+// refreshSidePanels reloads the side panels, each on its own background worker
+// so that they load in parallel
 func (gui *Gui) refreshSidePanels(g *gocui.Gui) error {
 	if err := gui.refreshBranches(g); err != nil {
 		return err
 	}
-	if err := gui.refreshFiles(); err != nil {
-		return err
-	}
+	gui.refreshFilesInBackground()
 	if err := gui.refreshCommits(g); err != nil {
 		return err
 	}
@@ -114,14 +118,20 @@ func (gui *Gui) newLineFocused(g *gocui.Gui, v *gocui.View) error {
 		return nil
 	case "commitMessage":
 		return gui.handleCommitFocused(g, v)
+	case "commitDescription":
+		return gui.handleCommitDescriptionFocused(g, v)
 	case "credentials":
 		return gui.handleCredentialsViewFocused(g, v)
+	case "hookOutput":
+		return gui.handleHookOutputFocused(g, v)
 	case "main":
 		if gui.State.Contexts["main"] == "merging" {
 			return gui.refreshMergePanel()
 		}
 		v.Highlight = false
 		return nil
+	case "commandLog":
+		return gui.renderCommandLog()
 	default:
 		panic(gui.Tr.SLocalize("NoViewMachingNewLineFocusedSwitchStatement"))
 	}
@@ -200,15 +210,29 @@ func (gui *Gui) switchFocus(g *gocui.Gui, oldView, newView *gocui.View) error {
 
 func (gui *Gui) resetOrigin(v *gocui.View) error {
 	_ = v.SetCursor(0, 0)
-	return v.SetOrigin(0, 0)
+	if err := v.SetOrigin(0, 0); err != nil {
+		return err
+	}
+	// the top of the content may not be what we've written to the view
+	if gui.viewWindow(v.Name()).Start > 0 {
+		if v.Name() == "main" {
+			gui.writeMainWindow(v, 0, 0)
+		} else {
+			gui.drawList(v, 0)
+		}
+	}
+	return nil
 }
 
 // if the cursor down past the last item, move it to the last line
 func (gui *Gui) focusPoint(cx int, cy int, lineCount int, v *gocui.View) error {
+	// a filtered list only shows some of its items, so the item's line isn't its index
+	cy, lineCount = gui.filteredLine(v.Name(), cy, lineCount)
 	if cy < 0 || cy > lineCount {
 		return nil
 	}
-	ox, oy := v.Origin()
+	ox, _ := v.Origin()
+	oy := gui.listTopLine(v)
 	_, height := v.Size()
 
 	ly := height - 1
@@ -221,16 +245,19 @@ func (gui *Gui) focusPoint(cx int, cy int, lineCount int, v *gocui.View) error {
 	// otherwise set cursor to value - origin
 	if ly > lineCount {
 		_ = v.SetCursor(cx, cy)
-		_ = v.SetOrigin(ox, 0)
+		oy = 0
 	} else if cy < oy {
 		_ = v.SetCursor(cx, 0)
-		_ = v.SetOrigin(ox, cy)
+		oy = cy
 	} else if cy > oy+ly {
 		_ = v.SetCursor(cx, ly)
-		_ = v.SetOrigin(ox, cy-ly)
+		oy = cy - ly
 	} else {
 		_ = v.SetCursor(cx, cy-oy)
 	}
+	if !gui.scrollListTo(v, ox, oy, lineCount) {
+		gui.renderSelectedLine(v)
+	}
 	return nil
 }
 
@@ -240,11 +267,60 @@ func (gui *Gui) cleanString(s string) string {
 }
 
 func (gui *Gui) setViewContent(g *gocui.Gui, v *gocui.View, s string) error {
+	content := gui.cleanString(s)
+	if v.Name() == "main" {
+		// whatever was there is being replaced, including a diff that's still
+		// coming in, so it's no longer ours to re-render
+		gui.dropBackgroundLoads("main")
+		gui.State.MainDiff = ""
+		gui.setMainLines(v, strings.Split(content, "\n"))
+		return nil
+	}
+	// all of it is written, so there's no window onto it
+	delete(gui.State.ViewWindows, v.Name())
 	v.Clear()
-	fmt.Fprint(v, gui.cleanString(s))
+	fmt.Fprint(v, content)
+	return nil
+}
+
+// setDiffContent puts a diff in the main view, laid out side-by-side if that's
+// been toggled on
+func (gui *Gui) setDiffContent(v *gocui.View, diff string) error {
+	width, _ := v.Size()
+	content := diff
+	if gui.State.SideBySideDiff {
+		content = git.SideBySideDiff(diff, width)
+	}
+	gui.State.MainDiff = diff
+	gui.State.MainDiffWidth = width
+	gui.setMainLines(v, strings.Split(gui.cleanString(content), "\n"))
 	return nil
 }
 
+// shouldHighlightDiffs is false when syntax highlighting is off, and with word
+// diffs, whose lines we can't pick apart without losing git's coloring
+func (gui *Gui) shouldHighlightDiffs() bool {
+	if len(theme.SyntaxColors) == 0 {
+		return false
+	}
+	return gui.GitCommand.DiffOptions == nil || !gui.GitCommand.DiffOptions.WordDiff
+}
+
+// handleToggleSideBySideDiff switches the diff in the main view between the
+// unified and side-by-side layouts. A diff that's still coming in carries on
+// in the new layout
+func (gui *Gui) handleToggleSideBySideDiff(g *gocui.Gui, v *gocui.View) error {
+	gui.State.SideBySideDiff = !gui.State.SideBySideDiff
+	if gui.State.MainDiff == "" {
+		return nil
+	}
+	mainView := gui.getMainView()
+	if err := gui.resetWindow(mainView); err != nil {
+		return err
+	}
+	return gui.setDiffContent(mainView, gui.State.MainDiff)
+}
+
 // renderString resets the origin of a view and sets its content
 func (gui *Gui) renderString(g *gocui.Gui, viewName, s string) error {
 	g.Update(func(*gocui.Gui) error {
@@ -252,7 +328,7 @@ func (gui *Gui) renderString(g *gocui.Gui, viewName, s string) error {
 		if err != nil {
 			return nil // return gracefully if view has been deleted
 		}
-		if err := v.SetOrigin(0, 0); err != nil {
+		if err := gui.resetWindow(v); err != nil {
 			return err
 		}
 		return gui.setViewContent(gui.g, v, s)
@@ -290,6 +366,11 @@ func (gui *Gui) getCommitMessageView() *gocui.View {
 	return v
 }
 
+func (gui *Gui) getCommitDescriptionView() *gocui.View {
+	v, _ := gui.g.View("commitDescription")
+	return v
+}
+
 func (gui *Gui) getBranchesView() *gocui.View {
 	v, _ := gui.g.View("branches")
 	return v
@@ -321,6 +402,15 @@ func (gui *Gui) currentViewName() string {
 
 func (gui *Gui) resizeCurrentPopupPanel(g *gocui.Gui) error {
 	v := g.CurrentView()
+	if v.Name() == "commitMessage" || v.Name() == "commitDescription" {
+		return gui.resizeCommitMessagePanels(g)
+	}
+	if v.Name() == "hookOutput" {
+		return gui.resizeHookOutputPanel(g)
+	}
+	if v.Name() == "filter" {
+		return gui.resizeFilterPanel(g)
+	}
 	if gui.isPopupPanel(v.Name()) {
 		return gui.resizePopupPanel(g, v)
 	}
@@ -357,7 +447,19 @@ func (gui *Gui) generalFocusLine(lineNumber int, bottomLine int, v *gocui.View)
 	return nil
 }
 
-func (gui *Gui) changeSelectedLine(line *int, total int, up bool) {
+// changeSelectedLine moves the selection of a list up or down by one item,
+// skipping over any that the list's filter hides
+func (gui *Gui) changeSelectedLine(viewName string, line *int, total int, up bool) {
+	if filter, ok := gui.State.Filters[viewName]; ok {
+		position := filteredPosition(filter.Indices, *line)
+		if position == -1 {
+			return
+		}
+		gui.changeSelectedLine("", &position, len(filter.Indices), up)
+		*line = filter.Indices[position]
+		return
+	}
+
 	if up {
 		if *line == -1 || *line == 0 {
 			return
@@ -388,13 +490,71 @@ func (gui *Gui) renderListPanel(v *gocui.View, items interface{}) error {
 		if err != nil {
 			return gui.createErrorPanel(gui.g, err.Error())
 		}
-		v.Clear()
-		fmt.Fprint(v, list)
+		gui.renderListContent(v, list)
 		return nil
 	})
 	return nil
 }
 
+// renderListContent writes a rendered list to its view, keeping hold of it so
+// that it can be redrawn as the selection moves or a search changes
+func (gui *Gui) renderListContent(v *gocui.View, list string) {
+	gui.State.RenderedLists[v.Name()] = list
+	gui.applyFilter(v)
+	gui.redrawList(v)
+}
+
+// matches the escape sequences that reset the style of text
+var resetSequenceRegexp = regexp.MustCompile(`\x1b\[0?m`)
+
+// renderSelectedLine redraws a list with the selected line highlighted if the
+// list has focus, or without any highlight if it doesn't. gocui can only show
+// the selected line in bold, so if the theme gives it a background we do that
+// ourselves
+func (gui *Gui) renderSelectedLine(v *gocui.View) {
+	if theme.SelectedLineBgColor.IsEmpty() {
+		return
+	}
+	gui.redrawList(v)
+}
+
+// redrawList writes a list to its view again with the items its filter lets
+// through, any search matches and the selected line's background
+func (gui *Gui) redrawList(v *gocui.View) {
+	gui.drawList(v, gui.listTopLine(v))
+}
+
+// drawList is redrawList with the given item at the top of the view. Only the
+// items around the ones on screen are written to the view
+func (gui *Gui) drawList(v *gocui.View, topLine int) {
+	list, ok := gui.State.RenderedLists[v.Name()]
+	if !ok {
+		return
+	}
+	lines := gui.filterLines(v.Name(), strings.Split(list, "\n"))
+	lines = gui.highlightSearchMatches(v, lines)
+	topLine = utils.Min(topLine, len(lines))
+	_, cy := v.Cursor()
+	selectedLine := topLine + cy
+	if !theme.SelectedLineBgColor.IsEmpty() && v == gui.g.CurrentView() && selectedLine < len(lines) {
+		width, _ := v.Size()
+		line := lines[selectedLine]
+		padding := width - utf8.RuneCountInString(utils.Decolorise(line))
+		if padding < 0 {
+			padding = 0
+		}
+		// the line's own styling ends with a reset, after which we need to
+		// bring the background back
+		sequence := theme.SelectedLineBgColor.Sequence()
+		line = resetSequenceRegexp.ReplaceAllStringFunc(line, func(reset string) string {
+			return reset + sequence
+		})
+		lines[selectedLine] = sequence + line + strings.Repeat(" ", padding) + "\x1b[0m"
+	}
+	start, end := windowRange(v, len(lines), topLine)
+	gui.writeWindow(v, lines[start:end], start, topLine, 0)
+}
+
 func (gui *Gui) renderPanelOptions() error {
 	currentView := gui.g.CurrentView()
 	switch currentView.Name() {
@@ -413,8 +573,59 @@ func (gui *Gui) handleFocusView(g *gocui.Gui, v *gocui.View) error {
 	return err
 }
 
+// listLength returns how many items a list panel has in full
+func (gui *Gui) listLength(viewName string) int {
+	switch viewName {
+	case "files":
+		return len(gui.State.Files)
+	case "branches":
+		return len(gui.State.Branches)
+	case "commits":
+		return len(gui.State.Commits)
+	case "commitFiles":
+		return len(gui.State.CommitFiles)
+	case "stash":
+		return len(gui.State.StashEntries)
+	case "menu":
+		return gui.State.MenuItemCount
+	}
+	return 0
+}
+
+// clickedItem returns the index of the list item that was clicked on, or -1
+// if the click wasn't on an item. gocui moves the cursor to wherever the mouse
+// was clicked before calling the handler, except for clicks on the frame, in
+// which case the cursor stays on the selected item
+func (gui *Gui) clickedItem(v *gocui.View) int {
+	_, cy := v.Cursor()
+	index := gui.filteredIndex(v.Name(), gui.listTopLine(v)+cy)
+	if index < 0 || index >= gui.listLength(v.Name()) {
+		return -1
+	}
+	return index
+}
+
+// handleListClick selects the item that was clicked on in a side panel and
+// focuses the panel, so clicking a panel's title just focuses it
+func (gui *Gui) handleListClick(g *gocui.Gui, v *gocui.View) error {
+	if gui.popupPanelFocused() {
+		return nil
+	}
+
+	if selectedLine := gui.selectedLinePointer(v.Name()); selectedLine != nil {
+		if index := gui.clickedItem(v); index != -1 {
+			*selectedLine = index
+		}
+	}
+
+	if gui.currentViewName() != v.Name() {
+		return gui.switchFocus(g, g.CurrentView(), v)
+	}
+	return gui.newLineFocused(g, v)
+}
+
 func (gui *Gui) isPopupPanel(viewName string) bool {
-	return viewName == "commitMessage" || viewName == "credentials" || viewName == "confirmation" || viewName == "menu"
+	return viewName == "commitMessage" || viewName == "commitDescription" || viewName == "credentials" || viewName == "confirmation" || viewName == "menu" || viewName == "hookOutput" || viewName == "filter"
 }
 
 func (gui *Gui) popupPanelFocused() bool {
@@ -0,0 +1,185 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/syntax"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// how many screens' worth of lines we write to a view either side of the ones
+// on screen, so that scrolling a little way doesn't mean writing it again
+const viewWindowMargin = 2
+
+// viewWindow is the range of lines of a view's content that we've written to
+// the view. Writing thousands of branches or the diff of a generated file to a
+// view every time it changes would hold everything up, so the main view and
+// the list panels only get the lines around the ones on screen, and the view's
+// origin is relative to the first of them
+type viewWindow struct {
+	Start int
+	End   int
+}
+
+func (gui *Gui) viewWindow(viewName string) *viewWindow {
+	window, ok := gui.State.ViewWindows[viewName]
+	if !ok {
+		window = &viewWindow{}
+		gui.State.ViewWindows[viewName] = window
+	}
+	return window
+}
+
+// windowRange returns the range of lines of a view's content that we write to
+// it to show the given line at the top
+func windowRange(v *gocui.View, lineCount int, topLine int) (int, int) {
+	_, height := v.Size()
+	margin := viewWindowMargin * utils.Max(height, 1)
+	return utils.Max(0, topLine-margin), utils.Min(lineCount, topLine+height+margin)
+}
+
+// windowNeedsMoving tells us whether a view has been scrolled close enough to
+// either end of what we've written to it that we should write the lines around
+// where it's got to
+func windowNeedsMoving(v *gocui.View, window *viewWindow, lineCount int, topLine int) bool {
+	_, height := v.Size()
+	return (window.Start > 0 && topLine-window.Start < height) ||
+		(window.End < lineCount && window.End-topLine < 2*height)
+}
+
+// writeWindow writes some lines of a view's content, the first of which is line
+// start, to the view, and scrolls it to the given line, less rowOffset of its
+// rows if it's wrapped
+func (gui *Gui) writeWindow(v *gocui.View, lines []string, start int, topLine int, rowOffset int) {
+	window := gui.viewWindow(v.Name())
+	window.Start, window.End = start, start+len(lines)
+	v.Clear()
+	fmt.Fprint(v, strings.Join(lines, "\n"))
+	ox, _ := v.Origin()
+	_ = v.SetOrigin(ox, wrappedRow(v, lines, topLine-start)+rowOffset)
+}
+
+// resetWindow scrolls a view back to the top before its content is replaced,
+// so there's no point writing what's there again
+func (gui *Gui) resetWindow(v *gocui.View) error {
+	delete(gui.State.ViewWindows, v.Name())
+	return v.SetOrigin(0, 0)
+}
+
+// listTopLine returns the line of a list panel's content at the top of the view
+func (gui *Gui) listTopLine(v *gocui.View) int {
+	_, oy := v.Origin()
+	return gui.viewWindow(v.Name()).Start + oy
+}
+
+// scrollListTo scrolls a view so that the given line of its content is at the
+// top. A list is written out again if that's far from what we've written of
+// it, in which case it returns true
+func (gui *Gui) scrollListTo(v *gocui.View, ox int, topLine int, lineCount int) bool {
+	window := gui.viewWindow(v.Name())
+	if _, ok := gui.State.RenderedLists[v.Name()]; ok {
+		if topLine < window.Start || windowNeedsMoving(v, window, lineCount, topLine) {
+			gui.drawList(v, topLine)
+			return true
+		}
+	}
+	_ = v.SetOrigin(ox, topLine-window.Start)
+	return false
+}
+
+// mainTopLine returns the line of the main view's content at the top of the
+// view, along with how many of its rows are scrolled off the top when it wraps
+func (gui *Gui) mainTopLine(v *gocui.View) (int, int) {
+	lines := gui.State.MainLines
+	window := gui.viewWindow("main")
+	_, oy := v.Origin()
+	end := utils.Min(window.End, len(lines))
+	row := 0
+	for i := window.Start; i < end; i++ {
+		rows := lineRows(v, lines[i])
+		if row+rows > oy {
+			return i, oy - row
+		}
+		row += rows
+	}
+	// we've scrolled past the bottom
+	return end, oy - row
+}
+
+// setMainLines puts content in the main view, keeping whichever line of the
+// old content was at the top of the view there
+func (gui *Gui) setMainLines(v *gocui.View, lines []string) {
+	topLine, rowOffset := gui.mainTopLine(v)
+	gui.State.MainLines = lines
+	gui.updateSearchMatches(v, lines)
+	gui.writeMainWindow(v, topLine, rowOffset)
+}
+
+// writeMainWindow writes the lines of the main view's content around the given
+// line to the view, highlighting the syntax of a diff and any search matches
+// as it goes, so that we only ever do that for the lines we write
+func (gui *Gui) writeMainWindow(v *gocui.View, topLine int, rowOffset int) {
+	lines := gui.State.MainLines
+	if topLine > len(lines) {
+		topLine, rowOffset = len(lines), 0
+	}
+	start, end := 0, len(lines)
+	if gui.State.Contexts["main"] == "normal" {
+		// the staging and merging panels work out which line is which from
+		// what's in the view, so they get all of it
+		start, end = windowRange(v, len(lines), topLine)
+	}
+	windowLines := lines[start:end]
+	if gui.highlightingMainDiff() {
+		windowLines = syntax.HighlightDiffLines(lines, start, end)
+	}
+	gui.writeWindow(v, gui.styleSearchMatches(v, windowLines), start, topLine, rowOffset)
+}
+
+// refreshMainWindow writes the lines around where the main view has been
+// scrolled to once it gets close to either end of what we've written to it
+func (gui *Gui) refreshMainWindow(v *gocui.View) {
+	if gui.State.Contexts["main"] != "normal" {
+		// all of it's been written
+		return
+	}
+	topLine, rowOffset := gui.mainTopLine(v)
+	if windowNeedsMoving(v, gui.viewWindow("main"), len(gui.State.MainLines), topLine) {
+		gui.writeMainWindow(v, topLine, rowOffset)
+	}
+}
+
+// scrollMainToLine scrolls the main view just far enough to bring a line of
+// its content on screen, e.g. to show a search match
+func (gui *Gui) scrollMainToLine(v *gocui.View, line int) {
+	lines := gui.State.MainLines
+	if line >= len(lines) {
+		return
+	}
+	topLine, rowOffset := gui.mainTopLine(v)
+	_, height := v.Size()
+	if line < topLine {
+		gui.writeMainWindow(v, line, 0)
+		return
+	}
+	if line-topLine < height && wrappedRow(v, lines[topLine:], line-topLine)-rowOffset < height {
+		return
+	}
+
+	// we bring it in at the bottom, so we work out how many lines above it fit
+	rows := lineRows(v, lines[line])
+	topLine = line
+	for topLine > 0 && rows+lineRows(v, lines[topLine-1]) <= height {
+		topLine--
+		rows += lineRows(v, lines[topLine])
+	}
+	gui.writeMainWindow(v, topLine, 0)
+}
+
+// highlightingMainDiff tells us whether the main view is showing a diff that
+// gets syntax highlighting
+func (gui *Gui) highlightingMainDiff() bool {
+	return gui.State.MainDiff != "" && !gui.State.SideBySideDiff && gui.shouldHighlightDiffs()
+}
@@ -61,9 +61,6 @@ func addDutch(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "AmendLastCommit",
 			Other: "wijzig laatste commit",
-		}, &i18n.Message{
-			ID:    "SureToAmend",
-			Other: "Weet je zeker dat je de laatste commit wilt wijzigen? U kunt het commit-bericht wijzigen vanuit het commits-paneel.",
 		}, &i18n.Message{
 			ID:    "NoCommitToAmend",
 			Other: "Er is geen commits om te wijzigen.",
@@ -76,6 +73,9 @@ func addDutch(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "GlobalTitle",
 			Other: "Global",
+		}, &i18n.Message{
+			ID:    "MenuTitle",
+			Other: "Menu",
 		}, &i18n.Message{
 			ID:    "navigate",
 			Other: "navigeer",
@@ -451,9 +451,6 @@ func addDutch(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "ConfirmRebase",
 			Other: "Weet je zeker dat je {{.checkedOutBranch}} op {{.selectedBranch}} wil rebasen?",
-		}, &i18n.Message{
-			ID:    "ConfirmMerge",
-			Other: "Weet je zeker dat je {{.selectedBranch}} in {{.checkedOutBranch}} wil mergen?",
 		}, &i18n.Message{
 			ID:    "FwdNoUpstream",
 			Other: "Kan niet de branch vooruitspoelen zonder upstream",
@@ -727,9 +724,6 @@ func addDutch(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "commitChangesWithoutHook",
 			Other: "commit veranderingen zonder pre-commit hook",
-		}, &i18n.Message{
-			ID:    "SkipHookPrefixNotConfigured",
-			Other: "Je hebt nog niet een commit bericht voorvoegsel ingesteld voor het overslaan van hooks. Set `git.skipHookPrefix = 'WIP'` in je config",
 		}, &i18n.Message{
 			ID:    "resetTo",
 			Other: `reset to`,
@@ -81,9 +81,6 @@ func addEnglish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "AmendLastCommit",
 			Other: "amend last commit",
-		}, &i18n.Message{
-			ID:    "SureToAmend",
-			Other: "Are you sure you want to amend last commit? Afterwards, you can change commit message from the commits panel.",
 		}, &i18n.Message{
 			ID:    "NoCommitToAmend",
 			Other: "There's no commit to amend.",
@@ -96,6 +93,9 @@ func addEnglish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "GlobalTitle",
 			Other: "Global",
+		}, &i18n.Message{
+			ID:    "MenuTitle",
+			Other: "Menu",
 		}, &i18n.Message{
 			ID:    "navigate",
 			Other: "navigate",
@@ -540,15 +540,9 @@ func addEnglish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "RebasingTitle",
 			Other: "Rebasing",
-		}, &i18n.Message{
-			ID:    "MergingTitle",
-			Other: "Merging",
 		}, &i18n.Message{
 			ID:    "ConfirmRebase",
 			Other: "Are you sure you want to rebase {{.checkedOutBranch}} onto {{.selectedBranch}}?",
-		}, &i18n.Message{
-			ID:    "ConfirmMerge",
-			Other: "Are you sure you want to merge {{.selectedBranch}} into {{.checkedOutBranch}}?",
 		}, &i18n.Message{}, &i18n.Message{
 			ID:    "FwdNoUpstream",
 			Other: "Cannot fast-forward a branch with no upstream",
@@ -750,9 +744,6 @@ func addEnglish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "commitChangesWithoutHook",
 			Other: "commit changes without pre-commit hook",
-		}, &i18n.Message{
-			ID:    "SkipHookPrefixNotConfigured",
-			Other: "You have not configured a commit message prefix for skipping hooks. Set `git.skipHookPrefix = 'WIP'` in your config",
 		}, &i18n.Message{
 			ID:    "resetTo",
 			Other: `reset to`,
@@ -777,6 +768,249 @@ func addEnglish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "jump",
 			Other: "jump to panel",
+		}, &i18n.Message{
+			ID:    "MergeStrategyTitle",
+			Other: "Merge {{.selectedBranch}} into {{.checkedOutBranch}}",
+		}, &i18n.Message{
+			ID:    "regularMerge",
+			Other: "regular merge",
+		}, &i18n.Message{
+			ID:    "noFastForwardMerge",
+			Other: "merge without fast-forwarding",
+		}, &i18n.Message{
+			ID:    "fastForwardOnlyMerge",
+			Other: "fast-forward only",
+		}, &i18n.Message{
+			ID:    "squashMerge",
+			Other: "squash merge (stage changes without committing)",
+		}, &i18n.Message{
+			ID:    "noFastForwardMergeWithMessage",
+			Other: "merge without fast-forwarding, editing the commit message",
+		}, &i18n.Message{
+			ID:    "MergeCommitMessage",
+			Other: "Merge commit message:",
+		}, &i18n.Message{
+			ID:    "NoUpstreamForBranch",
+			Other: "This branch has no upstream",
+		}, &i18n.Message{
+			ID:    "NoRemotes",
+			Other: "This repository has no remotes",
+		}, &i18n.Message{
+			ID:    "EnterRemote",
+			Other: "Remote:",
+		}, &i18n.Message{
+			ID:    "EnterRemoteBranch",
+			Other: "Remote branch:",
+		}, &i18n.Message{
+			ID:    "PushOptionsTitle",
+			Other: "Push options",
+		}, &i18n.Message{
+			ID:    "viewPushOptions",
+			Other: "view push options",
+		}, &i18n.Message{
+			ID:    "pushToUpstream",
+			Other: "push to upstream",
+		}, &i18n.Message{
+			ID:    "forcePushToUpstream",
+			Other: "force push to upstream",
+		}, &i18n.Message{
+			ID:    "pushWithoutHooks",
+			Other: "push without running the pre-push hook",
+		}, &i18n.Message{
+			ID:    "pushWithTags",
+			Other: "push including annotated tags",
+		}, &i18n.Message{
+			ID:    "pushToOtherBranch",
+			Other: "push to another remote/branch",
+		}, &i18n.Message{
+			ID:    "setUpstreamAndPush",
+			Other: "set upstream and push",
+		}, &i18n.Message{
+			ID:    "BranchesHaveDiverged",
+			Other: "Your branch and its upstream have diverged ({{.pushables}} local, {{.pullables}} upstream commits)",
+		}, &i18n.Message{
+			ID:    "pullModeMerge",
+			Other: "merge upstream into local branch",
+		}, &i18n.Message{
+			ID:    "pullModeRebase",
+			Other: "rebase local branch onto upstream",
+		}, &i18n.Message{
+			ID:    "pullModeFastForwardOnly",
+			Other: "fast-forward only",
+		}, &i18n.Message{
+			ID:    "pullModeAsk",
+			Other: "ask when branches have diverged",
+		}, &i18n.Message{
+			ID:    "setDefaultPullMode",
+			Other: "set default for diverged branches in this repo",
+		}, &i18n.Message{
+			ID:    "PullOptionsTitle",
+			Other: "Pull options",
+		}, &i18n.Message{
+			ID:    "DefaultPullModeTitle",
+			Other: "When pulling diverged branches in this repo",
+		}, &i18n.Message{
+			ID:    "viewPullOptions",
+			Other: "view pull options",
+		}, &i18n.Message{
+			ID:    "CredentialsPassphrase",
+			Other: "SSH key passphrase",
+		}, &i18n.Message{
+			ID:    "CredentialsHostKey",
+			Other: "Unknown host key, continue connecting? (yes/no)",
+		}, &i18n.Message{
+			ID:    "CredentialsOTP",
+			Other: "One-time password",
+		}, &i18n.Message{
+			ID:    "CommitDescription",
+			Other: "Description",
+		}, &i18n.Message{
+			ID:    "CommitMessageOptions",
+			Other: "{{.keyBindClose}}: close, {{.keyBindConfirm}}: confirm, {{.keyBindSwitch}}: edit description, {{.keyBindHistory}}: previous messages, {{.keyBindTrailer}}: add trailer, {{.keyBindHooks}}: toggle hooks",
+		}, &i18n.Message{
+			ID:    "CommitDescriptionOptions",
+			Other: "{{.keyBindClose}}: close, {{.keyBindConfirm}}: confirm, {{.keyBindNewLine}}: new line, {{.keyBindSwitch}}: edit summary, {{.keyBindTrailer}}: add trailer, {{.keyBindHooks}}: toggle hooks",
+		}, &i18n.Message{
+			ID:    "SubjectTooLong",
+			Other: "summary is longer than {{.max}} characters",
+		}, &i18n.Message{
+			ID:    "SubjectDoesNotMatchPattern",
+			Other: "summary does not match {{.pattern}}",
+		}, &i18n.Message{
+			ID:    "SubjectEndsWithPeriod",
+			Other: "summary ends with a period",
+		}, &i18n.Message{
+			ID:    "AddTrailerTitle",
+			Other: "Add trailer",
+		}, &i18n.Message{
+			ID:    "NoTrailerValues",
+			Other: "No one to add: there are no commits yet and git.trailers.people is empty",
+		}, &i18n.Message{
+			ID:    "CommandFailed",
+			Other: "Command failed",
+		}, &i18n.Message{
+			ID:    "CommittingStatus",
+			Other: "Committing",
+		}, &i18n.Message{
+			ID:    "SkippingHooks",
+			Other: "skipping hooks",
+		}, &i18n.Message{
+			ID:    "HookOutputRunningOptions",
+			Other: "{{.keyBindCancel}}: cancel, {{.keyBindScroll}}: scroll",
+		}, &i18n.Message{
+			ID:    "HookOutputOptions",
+			Other: "{{.keyBindClose}}: close, {{.keyBindScroll}}: scroll",
+		}, &i18n.Message{
+			ID:    "HookOutputRetryOptions",
+			Other: "{{.keyBindClose}}: close, {{.keyBindRetry}}: retry with --no-verify, {{.keyBindScroll}}: scroll",
+		}, &i18n.Message{
+			ID:    "InvalidCustomCommandPromptType",
+			Other: `Unknown custom command prompt type "{{.type}}", expected input or menu`,
+		}, &i18n.Message{
+			ID:    "CustomCommandMenuWithoutOptions",
+			Other: "This custom command menu has no options",
+		}, &i18n.Message{
+			ID:    "RunningCustomCommandStatus",
+			Other: "running custom command",
+		}, &i18n.Message{
+			ID:    "KeybindingConflict",
+			Other: "{{.key}} is bound to both {{.first}} and {{.other}} in the {{.view}} view",
+		}, &i18n.Message{
+			ID:    "toggleSideBySideDiff",
+			Other: "toggle side-by-side diff",
+		}, &i18n.Message{
+			ID:    "viewDiffOptions",
+			Other: "view diff options",
+		}, &i18n.Message{
+			ID:    "DiffOptionsTitle",
+			Other: "Diff options",
+		}, &i18n.Message{
+			ID:    "increaseContextLines",
+			Other: "show more context lines",
+		}, &i18n.Message{
+			ID:    "decreaseContextLines",
+			Other: "show fewer context lines",
+		}, &i18n.Message{
+			ID:    "toggleIgnoreWhitespace",
+			Other: "ignore whitespace",
+		}, &i18n.Message{
+			ID:    "toggleWordDiff",
+			Other: "word diff",
+		}, &i18n.Message{
+			ID:    "setRenameThreshold",
+			Other: "rename detection threshold",
+		}, &i18n.Message{
+			ID:    "setCopyThreshold",
+			Other: "copy detection threshold",
+		}, &i18n.Message{
+			ID:    "optionOn",
+			Other: "on",
+		}, &i18n.Message{
+			ID:    "optionOff",
+			Other: "off",
+		}, &i18n.Message{
+			ID:    "gitDefault",
+			Other: "git default",
+		}, &i18n.Message{
+			ID:    "ThresholdPrompt",
+			Other: "Similarity % (0 for git's default):",
+		}, &i18n.Message{
+			ID:    "InvalidThreshold",
+			Other: "The threshold must be a number from 0 to 100",
+		}, &i18n.Message{
+			ID:    "startSearch",
+			Other: "search the current panel",
+		}, &i18n.Message{
+			ID:    "startSearchMain",
+			Other: "search the main panel",
+		}, &i18n.Message{
+			ID:    "SearchPrompt",
+			Other: "Search:",
+		}, &i18n.Message{
+			ID:    "SearchNoMatches",
+			Other: "no matches for '{{.query}}'",
+		}, &i18n.Message{
+			ID:    "SearchMatchCount",
+			Other: "{{.count}} matches for '{{.query}}'",
+		}, &i18n.Message{
+			ID:    "SearchMatchPosition",
+			Other: "match {{.index}} of {{.count}} for '{{.query}}'",
+		}, &i18n.Message{
+			ID:    "filterList",
+			Other: "filter the list",
+		}, &i18n.Message{
+			ID:    "FilterTitle",
+			Other: "Filter",
+		}, &i18n.Message{
+			ID:    "FilterSubtitle",
+			Other: "{{.query}}: {{.count}} of {{.total}}",
+		}, &i18n.Message{
+			ID:    "nextScreenMode",
+			Other: "next screen mode (normal/half/fullscreen)",
+		}, &i18n.Message{
+			ID:    "prevScreenMode",
+			Other: "prev screen mode",
+		}, &i18n.Message{
+			ID:    "CommandLogTitle",
+			Other: "Command Log",
+		}, &i18n.Message{
+			ID:    "CommandLogNoOutput",
+			Other: "(no output)",
+		}, &i18n.Message{
+			ID:    "toggleCommandLog",
+			Other: "show/hide the command log",
+		}, &i18n.Message{
+			ID:    "focusCommandLog",
+			Other: "focus the command log",
+		}, &i18n.Message{
+			ID:    "toggleCommandOutput",
+			Other: "show/hide command output",
+		}, &i18n.Message{
+			ID:    "cancelCommand",
+			Other: "cancel the running command",
+		}, &i18n.Message{
+			ID:    "DiffTruncated",
+			Other: "diff cut short at {{.size}}MB, see maxDiffSize under git in your config",
 		},
 	)
 }
@@ -59,9 +59,6 @@ func addPolish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "AmendLastCommit",
 			Other: "zmień ostatnie zatwierdzenie",
-		}, &i18n.Message{
-			ID:    "SureToAmend",
-			Other: "Czy na pewno chcesz zmienić ostatnie zatwierdzenie? Możesz zmienić komunikat zatwierdzenia z panelu zatwierdzeń.",
 		}, &i18n.Message{
 			ID:    "NoCommitToAmend",
 			Other: "Nie ma zobowiązania do zmiany.",
@@ -74,6 +71,9 @@ func addPolish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "GlobalTitle",
 			Other: "Globalne",
+		}, &i18n.Message{
+			ID:    "MenuTitle",
+			Other: "Menu",
 		}, &i18n.Message{
 			ID:    "navigate",
 			Other: "nawiguj",
@@ -437,9 +437,6 @@ func addPolish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "ConfirmRebase",
 			Other: "Are you sure you want to rebase {{.checkedOutBranch}} onto {{.selectedBranch}}?",
-		}, &i18n.Message{
-			ID:    "ConfirmMerge",
-			Other: "Are you sure you want to merge {{.selectedBranch}} into {{.checkedOutBranch}}?",
 		}, &i18n.Message{}, &i18n.Message{
 			ID:    "FwdNoUpstream",
 			Other: "Cannot fast-forward a branch with no upstream",
@@ -710,9 +707,6 @@ func addPolish(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "commitChangesWithoutHook",
 			Other: "commit changes without pre-commit hook",
-		}, &i18n.Message{
-			ID:    "SkipHookPrefixNotConfigured",
-			Other: "You have not configured a commit message prefix for skipping hooks. Set `git.skipHookPrefix = 'WIP'` in your config",
 		}, &i18n.Message{
 			ID:    "resetTo",
 			Other: `reset to`,
@@ -0,0 +1,378 @@
+package syntax
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazygit/pkg/theme"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// the kinds of token we color, which are the keys of theme.SyntaxColors
+const (
+	Keyword = "keyword"
+	String  = "string"
+	Comment = "comment"
+	Number  = "number"
+)
+
+// Language holds what we need to know to pick out the tokens of a language.
+// We only go as far as keywords, strings, comments and numbers, which is
+// enough to make code easier to read without a parser for every language
+type Language struct {
+	Name         string
+	Keywords     map[string]bool
+	LineComments []string
+	// the start and end of a block comment, if the language has them
+	BlockComment []string
+	// the characters that start and end a string
+	Quotes string
+}
+
+func newLanguage(name string, keywords string, lineComments []string, blockComment []string, quotes string) *Language {
+	keywordSet := map[string]bool{}
+	for _, keyword := range strings.Fields(keywords) {
+		keywordSet[keyword] = true
+	}
+	return &Language{
+		Name:         name,
+		Keywords:     keywordSet,
+		LineComments: lineComments,
+		BlockComment: blockComment,
+		Quotes:       quotes,
+	}
+}
+
+var (
+	cStyleComments = []string{"/*", "*/"}
+
+	golang = newLanguage("go",
+		"break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false iota",
+		[]string{"//"}, cStyleComments, "\"'`")
+	javascript = newLanguage("javascript",
+		"async await break case catch class const continue debugger default delete do else export extends finally for from function if import in instanceof let new of return static super switch this throw try typeof var void while yield null undefined true false interface type enum implements",
+		[]string{"//"}, cStyleComments, "\"'`")
+	python = newLanguage("python",
+		"and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield None True False self",
+		[]string{"#"}, nil, "\"'")
+	ruby = newLanguage("ruby",
+		"alias and begin break case class def defined? do else elsif end ensure false for if in module next nil not or redo rescue retry return self super then true undef unless until when while yield require attr_accessor attr_reader",
+		[]string{"#"}, nil, "\"'")
+	rust = newLanguage("rust",
+		"as async await break const continue crate dyn else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while",
+		[]string{"//"}, cStyleComments, "\"")
+	c = newLanguage("c",
+		"auto break case char class const continue default delete do double else enum extern float for friend goto if inline int long namespace new nullptr private protected public register return short signed sizeof static struct switch template this throw try typedef typename union unsigned using virtual void volatile while bool true false NULL",
+		[]string{"//"}, cStyleComments, "\"'")
+	java = newLanguage("java",
+		"abstract boolean break byte case catch char class const continue default do double else enum extends final finally float for if implements import instanceof int interface long native new package private protected public return short static super switch synchronized this throw throws try void volatile while null true false val var fun when object",
+		[]string{"//"}, cStyleComments, "\"'")
+	shell = newLanguage("shell",
+		"if then else elif fi case esac for while until do done in function return local export readonly echo exit",
+		[]string{"#"}, nil, "\"'")
+	yaml = newLanguage("yaml", "true false null yes no", []string{"#"}, nil, "\"'")
+	json = newLanguage("json", "true false null", nil, nil, "\"")
+	css  = newLanguage("css", "important inherit initial none auto", nil, cStyleComments, "\"'")
+	sql  = newLanguage("sql",
+		"select from where insert into values update set delete create table drop alter index join left right inner outer on and or not null as order by group having limit primary key SELECT FROM WHERE INSERT INTO VALUES UPDATE SET DELETE CREATE TABLE DROP ALTER INDEX JOIN LEFT RIGHT INNER OUTER ON AND OR NOT NULL AS ORDER BY GROUP HAVING LIMIT PRIMARY KEY",
+		[]string{"--"}, cStyleComments, "'\"")
+	lua = newLanguage("lua",
+		"and break do else elseif end false for function goto if in local nil not or repeat return then true until while",
+		[]string{"--"}, nil, "\"'")
+)
+
+var languagesByExtension = map[string]*Language{
+	".go":   golang,
+	".js":   javascript,
+	".jsx":  javascript,
+	".ts":   javascript,
+	".tsx":  javascript,
+	".mjs":  javascript,
+	".py":   python,
+	".rb":   ruby,
+	".rs":   rust,
+	".c":    c,
+	".h":    c,
+	".cc":   c,
+	".cpp":  c,
+	".hpp":  c,
+	".cs":   java,
+	".java": java,
+	".kt":   java,
+	".sh":   shell,
+	".bash": shell,
+	".zsh":  shell,
+	".yml":  yaml,
+	".yaml": yaml,
+	".json": json,
+	".css":  css,
+	".scss": css,
+	".sql":  sql,
+	".lua":  lua,
+}
+
+// LanguageForFile returns the language of a file going by its extension, or
+// nil if it's not one we know
+func LanguageForFile(filename string) *Language {
+	return languagesByExtension[strings.ToLower(filepath.Ext(filename))]
+}
+
+// Highlighter colors the lines of a single file one after the other, keeping
+// track of whether we're part way through a block comment
+type Highlighter struct {
+	language       *Language
+	inBlockComment bool
+}
+
+// NewHighlighter returns a highlighter for the given language
+func NewHighlighter(language *Language) *Highlighter {
+	return &Highlighter{language: language}
+}
+
+// Reset forgets where we were, for when the next line doesn't follow on from
+// the last one
+func (h *Highlighter) Reset() {
+	h.inBlockComment = false
+}
+
+// HighlightLine colors the tokens in a line of code, giving anything that
+// isn't a token the base style
+func (h *Highlighter) HighlightLine(line string, base theme.TextStyle) string {
+	var output strings.Builder
+	h.highlight(line, func(text string, kind string) {
+		if text == "" {
+			return
+		}
+		style := base
+		if kindStyle, ok := theme.SyntaxColors[kind]; ok {
+			style = base.Add(kindStyle)
+		}
+		output.WriteString(style.Sprint(text))
+	})
+	return output.String()
+}
+
+// SkipLine takes in a line without coloring it, for when we only need to know
+// where it leaves us, e.g. part way through a block comment
+func (h *Highlighter) SkipLine(line string) {
+	h.highlight(line, func(string, string) {})
+}
+
+// highlight splits a line into tokens, handing each of them to write along with
+// its kind, and the text in between them with no kind
+func (h *Highlighter) highlight(line string, write func(text string, kind string)) {
+	lang := h.language
+	// the start of the text since the last token, which gets the base style
+	plainStart := 0
+	token := func(start, end int, kind string) {
+		write(line[plainStart:start], "")
+		write(line[start:end], kind)
+		plainStart = end
+	}
+
+	for i := 0; i < len(line); {
+		rest := line[i:]
+
+		opensBlockComment := len(lang.BlockComment) == 2 && strings.HasPrefix(rest, lang.BlockComment[0])
+		if h.inBlockComment || opensBlockComment {
+			searchFrom := i
+			if !h.inBlockComment {
+				searchFrom += len(lang.BlockComment[0])
+				h.inBlockComment = true
+			}
+			end := strings.Index(line[searchFrom:], lang.BlockComment[1])
+			if end == -1 {
+				token(i, len(line), Comment)
+				break
+			}
+			h.inBlockComment = false
+			token(i, searchFrom+end+len(lang.BlockComment[1]), Comment)
+			i = plainStart
+			continue
+		}
+
+		if startsWithAny(rest, lang.LineComments) {
+			token(i, len(line), Comment)
+			break
+		}
+
+		ch := rune(line[i])
+		afterWord := i > 0 && isWordChar(line[i-1])
+		switch {
+		case strings.ContainsRune(lang.Quotes, ch):
+			token(i, i+stringEnd(rest), String)
+		case isWordStart(ch) && !afterWord:
+			end := i + wordEnd(rest)
+			kind := ""
+			if lang.Keywords[line[i:end]] {
+				kind = Keyword
+			}
+			token(i, end, kind)
+		case unicode.IsDigit(ch) && !afterWord:
+			token(i, i+wordEnd(rest), Number)
+		default:
+			i++
+			continue
+		}
+		i = plainStart
+	}
+	write(line[plainStart:], "")
+}
+
+func startsWithAny(str string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(str, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringEnd returns where the string at the start of str ends, taking
+// escaped quotes into account. An unterminated string runs to the end
+func stringEnd(str string) int {
+	quote := str[0]
+	for i := 1; i < len(str); i++ {
+		switch str[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(str)
+}
+
+func isWordStart(ch rune) bool {
+	return ch == '_' || ch >= 0x80 || unicode.IsLetter(ch)
+}
+
+func isWordChar(ch byte) bool {
+	return ch == '_' || ch == '?' || ch >= 0x80 || unicode.IsLetter(rune(ch)) || unicode.IsDigit(rune(ch))
+}
+
+func wordEnd(str string) int {
+	for i := 0; i < len(str); i++ {
+		if !isWordChar(str[i]) {
+			return i
+		}
+	}
+	return len(str)
+}
+
+// HighlightDiff colors the code in a diff's hunks, going by the extension of
+// the file each hunk belongs to. Added and removed lines keep their green and
+// red, with the tokens colored on top. Only the lines from 'from' up to 'to'
+// are highlighted, so that a huge diff costs little more than the part of it
+// on screen; the rest are left as they came
+func HighlightDiff(diff string, from, to int) string {
+	if len(theme.SyntaxColors) == 0 {
+		return diff
+	}
+
+	lines := strings.Split(diff, "\n")
+	plainLines := strings.Split(utils.Decolorise(diff), "\n")
+	to = utils.Min(to, len(lines))
+
+	// a line can carry on from the lines before it, e.g. in the middle of a
+	// block comment, so the highlighter takes in the lines of the hunk that
+	// come before 'from' too
+	skipFrom := utils.Min(from, to)
+	for skipFrom > 0 && !isDiffHeader(plainLines[skipFrom-1]) {
+		skipFrom--
+	}
+
+	var highlighter *Highlighter
+	inHunk := false
+	for i := 0; i < to; i++ {
+		line := plainLines[i]
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			// e.g. 'diff --git a/old b/new', so we take the last field
+			fields := strings.Fields(line)
+			highlighter = nil
+			if language := LanguageForFile(fields[len(fields)-1]); language != nil {
+				highlighter = NewHighlighter(language)
+			}
+			inHunk = false
+			continue
+		case strings.HasPrefix(line, "@@@"):
+			// combined diffs have more than one column of +/-, so we leave them be
+			inHunk = false
+			continue
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+			if highlighter != nil {
+				highlighter.Reset()
+			}
+			continue
+		}
+
+		if i < skipFrom || !inHunk || highlighter == nil || line == "" {
+			continue
+		}
+
+		base := theme.TextStyle{}
+		switch line[0] {
+		case '+':
+			base = theme.NewTextStyle(color.FgGreen)
+		case '-':
+			base = theme.NewTextStyle(color.FgRed)
+		case ' ':
+		default:
+			continue
+		}
+		if i < from {
+			highlighter.SkipLine(line[1:])
+			continue
+		}
+		lines[i] = base.Sprint(line[:1]) + highlighter.HighlightLine(line[1:], base)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// isDiffHeader tells us whether a decolorised line of a diff starts a file or
+// a hunk, after which the highlighter starts afresh
+func isDiffHeader(line string) bool {
+	return strings.HasPrefix(line, "diff ") || strings.HasPrefix(line, "@@")
+}
+
+// HighlightDiffLines is HighlightDiff for the lines of a diff from 'from' up
+// to 'to', returning just those lines. How a line is highlighted depends on
+// the file it's in and on the lines of its hunk before it, so rather than going
+// through every line of the diff before them we go back as far as the header
+// of their file, and take in the lines of their hunk from its header on
+func HighlightDiffLines(lines []string, from, to int) []string {
+	if len(theme.SyntaxColors) == 0 || from >= to {
+		return lines[from:to]
+	}
+
+	fileHeader := []string{}
+	hunkStart := from
+	for i := from - 1; i >= 0; i-- {
+		// most lines can't be headers, and we can tell without decolorising them
+		if !strings.Contains(lines[i], "diff ") && !strings.Contains(lines[i], "@@") {
+			continue
+		}
+		plain := utils.Decolorise(lines[i])
+		if strings.HasPrefix(plain, "diff ") {
+			fileHeader = []string{lines[i]}
+			if hunkStart == from {
+				// there's no hunk between the file header and the window
+				hunkStart = i + 1
+			}
+			break
+		}
+		if hunkStart == from && strings.HasPrefix(plain, "@@") {
+			hunkStart = i
+		}
+	}
+
+	window := append(append(fileHeader, lines[hunkStart:from]...), lines[from:to]...)
+	start := len(window) - (to - from)
+	highlighted := HighlightDiff(strings.Join(window, "\n"), start, len(window))
+	return strings.Split(highlighted, "\n")[start:]
+}
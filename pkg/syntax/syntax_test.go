@@ -0,0 +1,214 @@
+package syntax
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazygit/pkg/theme"
+	"github.com/stretchr/testify/assert"
+)
+
+// useTestColors gives each kind of token a color that's easy to spot in the
+// expected output, returning a function that puts things back
+func useTestColors() func() {
+	noColor, syntaxColors := color.NoColor, theme.SyntaxColors
+	color.NoColor = false
+	theme.SyntaxColors = map[string]theme.TextStyle{
+		Keyword: theme.NewTextStyle(color.FgBlue),
+		String:  theme.NewTextStyle(color.FgYellow),
+		Comment: theme.NewTextStyle(color.FgCyan),
+		Number:  theme.NewTextStyle(color.FgMagenta),
+	}
+	return func() {
+		color.NoColor, theme.SyntaxColors = noColor, syntaxColors
+	}
+}
+
+// TestLanguageForFile is a function.
+func TestLanguageForFile(t *testing.T) {
+	assert.EqualValues(t, "go", LanguageForFile("pkg/gui/gui.go").Name)
+	assert.EqualValues(t, "yaml", LanguageForFile("config.YML").Name)
+	assert.Nil(t, LanguageForFile("Makefile"))
+}
+
+// TestHighlightLine is a function.
+func TestHighlightLine(t *testing.T) {
+	type scenario struct {
+		testName string
+		language *Language
+		lines    []string
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Keywords, strings and numbers",
+			golang,
+			[]string{`if x := "if"; y > 10 {`},
+			[]string{"\x1b[34mif\x1b[0m x := \x1b[33m\"if\"\x1b[0m; y > \x1b[35m10\x1b[0m {"},
+		},
+		{
+			"Keywords inside other words",
+			python,
+			[]string{"elif_ = notify"},
+			[]string{"elif_ = notify"},
+		},
+		{
+			"Escaped quotes and line comments",
+			golang,
+			[]string{`s := "a\"b" // done`},
+			[]string{"s := \x1b[33m\"a\\\"b\"\x1b[0m \x1b[36m// done\x1b[0m"},
+		},
+		{
+			"Block comments spanning lines",
+			c,
+			[]string{"int a; /* one", "two */ return"},
+			[]string{"\x1b[34mint\x1b[0m a; \x1b[36m/* one\x1b[0m", "\x1b[36mtwo */\x1b[0m \x1b[34mreturn\x1b[0m"},
+		},
+	}
+
+	defer useTestColors()()
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			highlighter := NewHighlighter(s.language)
+			output := []string{}
+			for _, line := range s.lines {
+				output = append(output, highlighter.HighlightLine(line, theme.TextStyle{}))
+			}
+			assert.EqualValues(t, s.expected, output)
+		})
+	}
+}
+
+// TestHighlightDiff is a function.
+func TestHighlightDiff(t *testing.T) {
+	diff := strings.Join([]string{
+		"diff --git a/main.go b/main.go",
+		"--- a/main.go",
+		"+++ b/main.go",
+		"@@ -1,2 +1,2 @@",
+		"-return 1",
+		"+return 2",
+		" x",
+		"diff --git a/notes.txt b/notes.txt",
+		"@@ -1 +1 @@",
+		"-return 1",
+	}, "\n")
+
+	type scenario struct {
+		testName string
+		from     int
+		to       int
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Whole diff",
+			0,
+			100,
+			[]string{
+				"diff --git a/main.go b/main.go",
+				"--- a/main.go",
+				"+++ b/main.go",
+				"@@ -1,2 +1,2 @@",
+				"\x1b[31m-\x1b[0m\x1b[31;34mreturn\x1b[0m\x1b[31m \x1b[0m\x1b[31;35m1\x1b[0m",
+				"\x1b[32m+\x1b[0m\x1b[32;34mreturn\x1b[0m\x1b[32m \x1b[0m\x1b[32;35m2\x1b[0m",
+				" x",
+				"diff --git a/notes.txt b/notes.txt",
+				"@@ -1 +1 @@",
+				"-return 1",
+			},
+		},
+		{
+			"Only the visible lines",
+			5,
+			6,
+			[]string{
+				"diff --git a/main.go b/main.go",
+				"--- a/main.go",
+				"+++ b/main.go",
+				"@@ -1,2 +1,2 @@",
+				"-return 1",
+				"\x1b[32m+\x1b[0m\x1b[32;34mreturn\x1b[0m\x1b[32m \x1b[0m\x1b[32;35m2\x1b[0m",
+				" x",
+				"diff --git a/notes.txt b/notes.txt",
+				"@@ -1 +1 @@",
+				"-return 1",
+			},
+		},
+	}
+
+	defer useTestColors()()
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, strings.Split(HighlightDiff(diff, s.from, s.to), "\n"))
+		})
+	}
+}
+
+// TestHighlightDiffLines is a function.
+func TestHighlightDiffLines(t *testing.T) {
+	lines := []string{
+		"\x1b[1mdiff --git a/main.go b/main.go\x1b[m",
+		"--- a/main.go",
+		"+++ b/main.go",
+		"\x1b[36m@@ -1,3 +1,3 @@\x1b[m",
+		"-return 1",
+		"+return 2",
+		" x // diff @@",
+		"@@ -10 +10 @@",
+		"+return 3",
+		"diff --git a/notes.txt b/notes.txt",
+		"@@ -1 +1 @@",
+		"-return 1",
+	}
+
+	type scenario struct {
+		testName string
+		from     int
+		to       int
+	}
+
+	scenarios := []scenario{
+		{"Whole diff", 0, len(lines)},
+		{"Within the first hunk", 5, 7},
+		{"In a later hunk of the same file", 8, 9},
+		{"Across files", 6, 11},
+		{"In a file we can't highlight", 11, 12},
+		{"Nothing", 4, 4},
+		{"Nothing at the start", 0, 0},
+	}
+
+	defer useTestColors()()
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			expected := strings.Split(HighlightDiff(strings.Join(lines, "\n"), s.from, s.to), "\n")[s.from:s.to]
+			assert.EqualValues(t, expected, HighlightDiffLines(lines, s.from, s.to))
+		})
+	}
+}
+
+// TestHighlightDiffCarriesBlockComments is a function.
+func TestHighlightDiffCarriesBlockComments(t *testing.T) {
+	lines := []string{
+		"diff --git a/main.go b/main.go",
+		"@@ -1,3 +1,3 @@",
+		" /* one",
+		"-two",
+		"+three */ return",
+		"@@ -10 +10 @@",
+		"+four */ return",
+	}
+	diff := strings.Join(lines, "\n")
+
+	defer useTestColors()()
+	whole := strings.Split(HighlightDiff(diff, 0, len(lines)), "\n")
+	assert.EqualValues(t, "\x1b[32m+\x1b[0m\x1b[32;36mthree */\x1b[0m\x1b[32m \x1b[0m\x1b[32;34mreturn\x1b[0m", whole[4])
+
+	for from := 2; from < len(lines); from++ {
+		assert.EqualValues(t, whole[from:], strings.Split(HighlightDiff(diff, from, len(lines)), "\n")[from:])
+		assert.EqualValues(t, whole[from:], HighlightDiffLines(lines, from, len(lines)))
+	}
+}
@@ -1,6 +1,10 @@
 package theme
 
 import (
+	"os"
+	"strconv"
+	"strings"
+
 	"github.com/fatih/color"
 	"github.com/jesseduffield/gocui"
 	"github.com/spf13/viper"
@@ -18,44 +22,258 @@ var (
 
 	// InactiveBorderColor is the border color of the inactive active frames
 	InactiveBorderColor gocui.Attribute
+
+	// OptionsTextColor is the color of the keybinding hints at the bottom of the screen
+	OptionsTextColor gocui.Attribute
+
+	// SelectedLineBgColor is the background of the selected line of the focused
+	// list. It's empty if the selected line is only shown in bold
+	SelectedLineBgColor TextStyle
+
+	// CommitStatusColors maps the status of a commit e.g. "unpushed" to the
+	// color of its sha
+	CommitStatusColors map[string]TextStyle
+
+	// CherryPickedCommitColor is the color of the sha of a commit that has been
+	// copied for cherry-picking
+	CherryPickedCommitColor TextStyle
+
+	// RebaseActionColor is the color of a commit's action e.g. "pick" in an
+	// interactive rebase
+	RebaseActionColor TextStyle
+
+	// BranchColors maps the type of a branch, i.e. the part of its name before
+	// the first slash like "feature", to the color of its name
+	BranchColors map[string]TextStyle
+
+	// StagedColor and UnstagedColor are the colors of the changes in the files panel
+	StagedColor   TextStyle
+	UnstagedColor TextStyle
+
+	// ConflictMarkerColor is the color of the <<<<<<<, ======= and >>>>>>>
+	// lines of a merge conflict
+	ConflictMarkerColor TextStyle
+
+	// SelectedConflictColor is added to the lines of the hunk that's selected
+	// when resolving a merge conflict
+	SelectedConflictColor TextStyle
+
+	// SearchMatchColor is added to the text that matches a search
+	SearchMatchColor TextStyle
+
+	// SelectedRangeColor is added to the lines of a range being selected with
+	// the mouse in the staging panel
+	SelectedRangeColor TextStyle
+
+	// SyntaxColors maps a kind of token in highlighted code, like "keyword" or
+	// "comment", to its color. It's empty when syntax highlighting is off
+	SyntaxColors map[string]TextStyle
+
+	// Uses256Colors is true if the theme has a color that's only in the 256
+	// color palette, in which case the gui needs to be in 256 color mode
+	Uses256Colors bool
+
+	// NoColor is true if the NO_COLOR environment variable is set, in which case
+	// we only use text attributes like bold, and leave the colors alone
+	NoColor bool
 )
 
+// the built-in syntax highlighting styles, picked with gui.theme.syntaxStyle
+var syntaxStyles = map[string]map[string][]string{
+	"default": {
+		"keyword": {"blue", "bold"},
+		"string":  {"yellow"},
+		"comment": {"cyan"},
+		"number":  {"magenta"},
+	},
+	"monokai": {
+		"keyword": {"197"},
+		"string":  {"186"},
+		"comment": {"242"},
+		"number":  {"141"},
+	},
+	"monochrome": {
+		"keyword": {"bold"},
+	},
+	"none": {},
+}
+
+// the eight colors every terminal has, in palette order
+var colorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
 // UpdateTheme updates all theme variables
 func UpdateTheme(userConfig *viper.Viper) {
+	// see https://no-color.org
+	NoColor = os.Getenv("NO_COLOR") != ""
+	color.NoColor = color.NoColor || NoColor
+	Uses256Colors = false
+
 	ActiveBorderColor = getColor(userConfig.GetStringSlice("gui.theme.activeBorderColor"))
 	InactiveBorderColor = getColor(userConfig.GetStringSlice("gui.theme.inactiveBorderColor"))
+	OptionsTextColor = getColor(userConfig.GetStringSlice("gui.theme.optionsTextColor"))
 
-	isLightTheme := userConfig.GetBool("gui.theme.lightTheme")
-	if isLightTheme {
+	if isLightTheme(userConfig.GetString("gui.theme.lightTheme")) {
 		DefaultTextColor = color.FgBlack
 		GocuiDefaultTextColor = gocui.ColorBlack
 	} else {
 		DefaultTextColor = color.FgWhite
 		GocuiDefaultTextColor = gocui.ColorWhite
 	}
+
+	SelectedLineBgColor = getTextStyle(userConfig.GetStringSlice("gui.theme.selectedLineBgColor"), true)
+	CommitStatusColors = getTextStyleMap(userConfig.GetStringMapStringSlice("gui.theme.commitStatusColors"))
+	CherryPickedCommitColor = getTextStyle(userConfig.GetStringSlice("gui.theme.cherryPickedCommitFgColor"), false).Add(
+		getTextStyle(userConfig.GetStringSlice("gui.theme.cherryPickedCommitBgColor"), true),
+	)
+	RebaseActionColor = getTextStyle(userConfig.GetStringSlice("gui.theme.rebaseActionColor"), false)
+	BranchColors = getTextStyleMap(userConfig.GetStringMapStringSlice("gui.theme.branchColors"))
+	StagedColor = getTextStyle(userConfig.GetStringSlice("gui.theme.stagedColor"), false)
+	UnstagedColor = getTextStyle(userConfig.GetStringSlice("gui.theme.unstagedColor"), false)
+	ConflictMarkerColor = getTextStyle(userConfig.GetStringSlice("gui.theme.conflictMarkerColor"), false)
+	SelectedConflictColor = getTextStyle(userConfig.GetStringSlice("gui.theme.selectedConflictColor"), false)
+	SearchMatchColor = getTextStyle(userConfig.GetStringSlice("gui.theme.searchMatchColor"), false)
+	SelectedRangeColor = getTextStyle(userConfig.GetStringSlice("gui.theme.selectedRangeColor"), false)
+	SyntaxColors = getSyntaxColors(userConfig.GetString("gui.theme.syntaxStyle"), userConfig.GetStringMapStringSlice("gui.theme.syntaxColors"))
+}
+
+// getSyntaxColors takes the colors of one of the built-in syntax styles, falling
+// back to the default style for a name we don't know, and applies the user's
+// own colors for individual kinds of token on top
+func getSyntaxColors(styleName string, overrides map[string][]string) map[string]TextStyle {
+	style, ok := syntaxStyles[styleName]
+	if !ok {
+		style = syntaxStyles["default"]
+	}
+	keysMap := map[string][]string{}
+	for kind, keys := range style {
+		keysMap[kind] = keys
+	}
+	for kind, keys := range overrides {
+		keysMap[kind] = keys
+	}
+
+	syntaxColors := map[string]TextStyle{}
+	for kind, textStyle := range getTextStyleMap(keysMap) {
+		if !textStyle.IsEmpty() {
+			syntaxColors[kind] = textStyle
+		}
+	}
+	return syntaxColors
+}
+
+// isLightTheme takes the lightTheme setting, which is true, false or auto. With
+// auto we go by the COLORFGBG variable that many terminals set to the indices
+// of their foreground and background colors, e.g. "0;15" for black on white.
+// Without it we assume a dark background
+func isLightTheme(setting string) bool {
+	if setting != "auto" {
+		isLight, _ := strconv.ParseBool(setting)
+		return isLight
+	}
+	fields := strings.Split(os.Getenv("COLORFGBG"), ";")
+	background, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return false
+	}
+	// 7 is white, 9-15 are the bright colors, only the first of which is dark grey
+	return background == 7 || (background > 8 && background <= 15)
+}
+
+// paletteIndex returns the index in the 256 color palette of the color given
+// by name, by number, or as a hex value like #ff8700, which is rounded to the
+// nearest color in the palette. gocui only knows the 256 colors, so we can't
+// show a hex value exactly even if the terminal supports truecolor. The second
+// return value is false if the value isn't a color, e.g. if it's an attribute
+// like bold
+func paletteIndex(value string) (int, bool) {
+	for i, name := range colorNames {
+		if value == name {
+			return i, true
+		}
+	}
+	if strings.HasPrefix(value, "#") {
+		rgb, err := strconv.ParseUint(strings.TrimPrefix(value, "#"), 16, 32)
+		if err != nil || len(value) != 7 {
+			return 0, false
+		}
+		return nearestPaletteIndex(int(rgb>>16), int(rgb>>8&0xff), int(rgb&0xff)), true
+	}
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 0 || index > 255 {
+		return 0, false
+	}
+	return index, true
+}
+
+// the levels of red, green and blue that make up the 6x6x6 color cube at
+// indices 16-231 of the 256 color palette
+var cubeLevels = []int{0, 95, 135, 175, 215, 255}
+
+// nearestPaletteIndex finds the color closest to the given one among the color
+// cube and the greyscale ramp of the 256 color palette. We leave out the first
+// 16 colors because terminals are free to choose their own values for them
+func nearestPaletteIndex(r, g, b int) int {
+	nearestLevel := func(value int) int {
+		nearest := 0
+		for i, level := range cubeLevels {
+			if abs(level-value) < abs(cubeLevels[nearest]-value) {
+				nearest = i
+			}
+		}
+		return nearest
+	}
+	ri, gi, bi := nearestLevel(r), nearestLevel(g), nearestLevel(b)
+	cubeDistance := distance(r, g, b, cubeLevels[ri], cubeLevels[gi], cubeLevels[bi])
+
+	// the greyscale ramp goes from 8 to 238 in steps of 10
+	greyIndex := ((r+g+b)/3 - 8 + 5) / 10
+	if greyIndex < 0 {
+		greyIndex = 0
+	} else if greyIndex > 23 {
+		greyIndex = 23
+	}
+	grey := 8 + greyIndex*10
+	if distance(r, g, b, grey, grey, grey) < cubeDistance {
+		return 232 + greyIndex
+	}
+	return 16 + 36*ri + 6*gi + bi
+}
+
+func distance(r1, g1, b1, r2, g2, b2 int) int {
+	return (r1-r2)*(r1-r2) + (g1-g2)*(g1-g2) + (b1-b2)*(b1-b2)
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
 }
 
 // getAttribute gets the gocui color attribute from the string
 func getAttribute(key string) gocui.Attribute {
-	colorMap := map[string]gocui.Attribute{
-		"default":   gocui.ColorDefault,
-		"black":     gocui.ColorBlack,
-		"red":       gocui.ColorRed,
-		"green":     gocui.ColorGreen,
-		"yellow":    gocui.ColorYellow,
-		"blue":      gocui.ColorBlue,
-		"magenta":   gocui.ColorMagenta,
-		"cyan":      gocui.ColorCyan,
-		"white":     gocui.ColorWhite,
-		"bold":      gocui.AttrBold,
-		"reverse":   gocui.AttrReverse,
-		"underline": gocui.AttrUnderline,
-	}
-	value, present := colorMap[key]
-	if present {
-		return value
-	}
-	return gocui.ColorWhite
+	switch key {
+	case "default":
+		return gocui.ColorDefault
+	case "bold":
+		return gocui.AttrBold
+	case "reverse":
+		return gocui.AttrReverse
+	case "underline":
+		return gocui.AttrUnderline
+	}
+	index, ok := paletteIndex(key)
+	if !ok {
+		return gocui.ColorWhite
+	}
+	if NoColor {
+		return gocui.ColorDefault
+	}
+	if index >= len(colorNames) {
+		Uses256Colors = true
+	}
+	// gocui counts from 1, leaving 0 for the default color
+	return gocui.Attribute(index + 1)
 }
 
 // getColor bitwise OR's a list of attributes obtained via the given keys
@@ -69,32 +287,125 @@ func getColor(keys []string) gocui.Attribute {
 
 // GetAttribute gets the gocui color attribute from the string
 func GetAttribute(key string) gocui.Attribute {
-	colorMap := map[string]gocui.Attribute{
-		"default":   gocui.ColorDefault,
-		"black":     gocui.ColorBlack,
-		"red":       gocui.ColorRed,
-		"green":     gocui.ColorGreen,
-		"yellow":    gocui.ColorYellow,
-		"blue":      gocui.ColorBlue,
-		"magenta":   gocui.ColorMagenta,
-		"cyan":      gocui.ColorCyan,
-		"white":     gocui.ColorWhite,
-		"bold":      gocui.AttrBold,
-		"reverse":   gocui.AttrReverse,
-		"underline": gocui.AttrUnderline,
-	}
-	value, present := colorMap[key]
-	if present {
-		return value
-	}
-	return gocui.ColorWhite
+	return getAttribute(key)
 }
 
 // GetColor bitwise OR's a list of attributes obtained via the given keys
 func GetColor(keys []string) gocui.Attribute {
-	var attribute gocui.Attribute
+	return getColor(keys)
+}
+
+// TextStyle is a set of colors and attributes to style text with
+type TextStyle struct {
+	// gocui only understands a color from the 256 color palette when it's the
+	// only thing in its escape sequence, so each of those gets its own
+	// sequence, while the basic colors and attributes share the first one
+	sequences [][]color.Attribute
+}
+
+// NewTextStyle returns a style made up of the given basic colors and attributes
+func NewTextStyle(attributes ...color.Attribute) TextStyle {
+	return TextStyle{sequences: [][]color.Attribute{attributes}}
+}
+
+// Add returns a style with the colors and attributes of both styles, the
+// other style's colors taking precedence
+func (s TextStyle) Add(other TextStyle) TextStyle {
+	if len(s.sequences) == 0 {
+		return other
+	}
+	if len(other.sequences) == 0 {
+		return s
+	}
+	sequences := [][]color.Attribute{append(append([]color.Attribute{}, s.sequences[0]...), other.sequences[0]...)}
+	sequences = append(sequences, s.sequences[1:]...)
+	sequences = append(sequences, other.sequences[1:]...)
+	return TextStyle{sequences: sequences}
+}
+
+// IsEmpty tells us whether the style would leave text as it is
+func (s TextStyle) IsEmpty() bool {
+	for _, sequence := range s.sequences {
+		if len(sequence) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Sprint styles the given string
+func (s TextStyle) Sprint(str string) string {
+	// nesting the sequences works because each one only sets what it's given
+	for i := len(s.sequences) - 1; i >= 0; i-- {
+		if len(s.sequences[i]) > 0 {
+			str = color.New(s.sequences[i]...).Sprint(str)
+		}
+	}
+	return str
+}
+
+// Sequence returns the escape sequences that switch on the style, for when
+// it needs to be switched back on part way through a string
+func (s TextStyle) Sequence() string {
+	if color.NoColor {
+		return ""
+	}
+	sequence := ""
+	for _, attributes := range s.sequences {
+		if len(attributes) == 0 {
+			continue
+		}
+		values := make([]string, len(attributes))
+		for i, attribute := range attributes {
+			values[i] = strconv.Itoa(int(attribute))
+		}
+		sequence += "\x1b[" + strings.Join(values, ";") + "m"
+	}
+	return sequence
+}
+
+// getTextStyle gets the style for text with the colors and attributes named
+// by the given keys, with the colors as the foreground unless isBg is set.
+// Unknown keys and "default" are left out
+func getTextStyle(keys []string, isBg bool) TextStyle {
+	basic := []color.Attribute{}
+	sequences := [][]color.Attribute{}
 	for _, key := range keys {
-		attribute |= GetAttribute(key)
+		switch key {
+		case "bold":
+			basic = append(basic, color.Bold)
+			continue
+		case "reverse":
+			basic = append(basic, color.ReverseVideo)
+			continue
+		case "underline":
+			basic = append(basic, color.Underline)
+			continue
+		}
+		index, ok := paletteIndex(key)
+		if !ok || NoColor {
+			continue
+		}
+		switch {
+		case index < len(colorNames) && isBg:
+			basic = append(basic, color.BgBlack+color.Attribute(index))
+		case index < len(colorNames):
+			basic = append(basic, color.FgBlack+color.Attribute(index))
+		case isBg:
+			Uses256Colors = true
+			sequences = append(sequences, []color.Attribute{48, 5, color.Attribute(index)})
+		default:
+			Uses256Colors = true
+			sequences = append(sequences, []color.Attribute{38, 5, color.Attribute(index)})
+		}
 	}
-	return attribute
+	return TextStyle{sequences: append([][]color.Attribute{basic}, sequences...)}
+}
+
+func getTextStyleMap(keysMap map[string][]string) map[string]TextStyle {
+	styleMap := map[string]TextStyle{}
+	for name, keys := range keysMap {
+		styleMap[name] = getTextStyle(keys, false)
+	}
+	return styleMap
 }
@@ -0,0 +1,126 @@
+package theme
+
+import (
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPaletteIndex is a function.
+func TestPaletteIndex(t *testing.T) {
+	type scenario struct {
+		value         string
+		expected      int
+		expectedIsSet bool
+	}
+
+	scenarios := []scenario{
+		{"red", 1, true},
+		{"white", 7, true},
+		{"208", 208, true},
+		{"256", 0, false},
+		{"#ff8700", 208, true},
+		{"#000000", 16, true},
+		{"#808080", 244, true},
+		{"#ff87", 0, false},
+		{"bold", 0, false},
+	}
+
+	for _, s := range scenarios {
+		index, isSet := paletteIndex(s.value)
+		assert.EqualValues(t, s.expected, index, s.value)
+		assert.EqualValues(t, s.expectedIsSet, isSet, s.value)
+	}
+}
+
+// TestIsLightTheme is a function.
+func TestIsLightTheme(t *testing.T) {
+	type scenario struct {
+		setting   string
+		colorfgbg string
+		expected  bool
+	}
+
+	scenarios := []scenario{
+		{"true", "15;0", true},
+		{"false", "0;15", false},
+		{"auto", "0;15", true},
+		{"auto", "0;default;7", true},
+		{"auto", "15;0", false},
+		{"auto", "15;8", false},
+		{"auto", "", false},
+	}
+
+	defer os.Setenv("COLORFGBG", os.Getenv("COLORFGBG"))
+	for _, s := range scenarios {
+		os.Setenv("COLORFGBG", s.colorfgbg)
+		assert.EqualValues(t, s.expected, isLightTheme(s.setting), s.setting+" "+s.colorfgbg)
+	}
+}
+
+// TestTextStyleSprint is a function.
+func TestTextStyleSprint(t *testing.T) {
+	type scenario struct {
+		keys     []string
+		isBg     bool
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			[]string{"red", "bold"},
+			false,
+			"\x1b[31;1mtext\x1b[0m",
+		},
+		{
+			[]string{"208", "bold"},
+			false,
+			"\x1b[1m\x1b[38;5;208mtext\x1b[0m\x1b[0m",
+		},
+		{
+			[]string{"blue"},
+			true,
+			"\x1b[44mtext\x1b[0m",
+		},
+		{
+			[]string{"default"},
+			false,
+			"text",
+		},
+	}
+
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	color.NoColor = false
+	for _, s := range scenarios {
+		assert.EqualValues(t, s.expected, getTextStyle(s.keys, s.isBg).Sprint("text"))
+	}
+}
+
+// TestGetSyntaxColors is a function.
+func TestGetSyntaxColors(t *testing.T) {
+	type scenario struct {
+		styleName string
+		overrides map[string][]string
+		expected  []string
+	}
+
+	scenarios := []scenario{
+		{"default", nil, []string{"comment", "keyword", "number", "string"}},
+		{"unknown", nil, []string{"comment", "keyword", "number", "string"}},
+		{"monochrome", map[string][]string{"comment": {"underline"}}, []string{"comment", "keyword"}},
+		{"none", nil, []string{}},
+		{"default", map[string][]string{"string": {"default"}}, []string{"comment", "keyword", "number"}},
+	}
+
+	for _, s := range scenarios {
+		kinds := []string{}
+		for kind := range getSyntaxColors(s.styleName, s.overrides) {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		assert.EqualValues(t, s.expected, kinds, s.styleName)
+	}
+}
@@ -114,6 +114,14 @@ func Min(x, y int) int {
 	return y
 }
 
+// Max returns the maximum of two integers
+func Max(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
+
 type Displayable interface {
 	GetDisplayStrings(bool) []string
 }
@@ -163,10 +171,115 @@ func renderDisplayableList(items []Displayable, isFocused bool) (string, error)
 
 // Decolorise strips a string of color
 func Decolorise(str string) string {
-	re := regexp.MustCompile(`\x1B\[([0-9]{1,2}(;[0-9]{1,2})?)?[m|K]`)
+	re := regexp.MustCompile(`\x1B\[[0-9;]*[mK]`)
 	return re.ReplaceAllString(str, "")
 }
 
+var colorSequenceRegexp = regexp.MustCompile(`^\x1B\[[0-9;]*[mK]`)
+
+// StyleMatches switches on the given escape sequence for every occurrence of
+// query in the text of str, which may have color in it already. Matching is
+// case-insensitive unless the query has an uppercase letter in it, like vim's
+// smartcase. After each match, the colors that were in effect before it are
+// switched back on. It returns the styled string and the number of matches
+func StyleMatches(str string, query string, sequence string) (string, int) {
+	if query == "" {
+		return str, 0
+	}
+
+	text := Decolorise(str)
+
+	if strings.ToLower(query) == query && len(strings.ToLower(text)) == len(text) {
+		text = strings.ToLower(text)
+	}
+
+	// whether each byte of the text is part of a match
+	inMatch := make([]bool, len(text))
+	count := 0
+	for start := 0; start < len(text); {
+		index := strings.Index(text[start:], query)
+		if index == -1 {
+			break
+		}
+		for j := start + index; j < start+index+len(query); j++ {
+			inMatch[j] = true
+		}
+		count++
+		start += index + len(query)
+	}
+	if count == 0 {
+		return str, 0
+	}
+
+	var output strings.Builder
+	// the sequences in effect since the last reset, for after a match
+	active := ""
+	textIndex := 0
+	matching := false
+	for i := 0; i < len(str); {
+		if loc := colorSequenceRegexp.FindStringIndex(str[i:]); loc != nil {
+			escape := str[i : i+loc[1]]
+			if escape == "\x1b[0m" || escape == "\x1b[m" {
+				active = ""
+			} else {
+				active += escape
+			}
+			output.WriteString(escape)
+			if matching {
+				// the line's own colors mustn't switch the match's style off
+				output.WriteString(sequence)
+			}
+			i += loc[1]
+			continue
+		}
+
+		if inMatch[textIndex] && !matching {
+			output.WriteString(sequence)
+			matching = true
+		} else if !inMatch[textIndex] && matching {
+			output.WriteString("\x1b[0m" + active)
+			matching = false
+		}
+		output.WriteString(str[i : i+1])
+		textIndex++
+		i++
+	}
+	if matching {
+		output.WriteString("\x1b[0m" + active)
+	}
+
+	return output.String(), count
+}
+
+// FuzzyMatch reports whether each word of the query can be found in text with
+// its characters in order, though not necessarily next to each other, so that
+// e.g. "fbar" matches "feature/bar". Like StyleMatches, it's case-insensitive
+// unless the query has an uppercase letter in it
+func FuzzyMatch(query string, text string) bool {
+	if strings.ToLower(query) == query {
+		text = strings.ToLower(text)
+	}
+	for _, word := range strings.Fields(query) {
+		if !containsInOrder(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsInOrder reports whether all the characters of word appear in text
+// in the same order
+func containsInOrder(text string, word string) bool {
+	chars := []rune(word)
+	i := 0
+	for _, ch := range text {
+		if i < len(chars) && ch == chars[i] {
+			i++
+		}
+	}
+	return i == len(chars)
+}
+
 func getPadWidths(stringArrays [][]string) []int {
 	if len(stringArrays[0]) <= 1 {
 		return []int{}
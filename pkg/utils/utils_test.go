@@ -397,6 +397,10 @@ func TestGetPadWidths(t *testing.T) {
 			[][]string{{"aa", "b", "ccc"}, {"c", "d", "e"}},
 			[]int{2, 1},
 		},
+		{
+			[][]string{{"\x1b[1m\x1b[38;5;208maa\x1b[0m\x1b[0m", "b"}, {"c", "d"}},
+			[]int{2},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -435,6 +439,37 @@ func TestMin(t *testing.T) {
 	}
 }
 
+// TestMax is a function.
+func TestMax(t *testing.T) {
+	type scenario struct {
+		a        int
+		b        int
+		expected int
+	}
+
+	scenarios := []scenario{
+		{
+			1,
+			1,
+			1,
+		},
+		{
+			1,
+			2,
+			2,
+		},
+		{
+			2,
+			1,
+			2,
+		},
+	}
+
+	for _, s := range scenarios {
+		assert.EqualValues(t, s.expected, Max(s.a, s.b))
+	}
+}
+
 // TestIncludesString is a function.
 func TestIncludesString(t *testing.T) {
 	type scenario struct {
@@ -573,3 +608,52 @@ func TestAsJson(t *testing.T) {
 	// no idea why this is returning empty hashes but it's works in the app ¯\_(ツ)_/¯
 	assert.EqualValues(t, "{}", output)
 }
+
+// TestStyleMatches is a function.
+func TestStyleMatches(t *testing.T) {
+	type scenario struct {
+		str           string
+		query         string
+		expected      string
+		expectedCount int
+	}
+
+	scenarios := []scenario{
+		{"no match here", "xyz", "no match here", 0},
+		{"Foo and foo", "foo", "<Foo\x1b[0m and <foo\x1b[0m", 2},
+		{"Foo and foo", "Foo", "<Foo\x1b[0m and foo", 1},
+		{"\x1b[31mred\x1b[0m text", "d te", "\x1b[31mre<d\x1b[0m< te\x1b[0mxt", 1},
+		{"\x1b[32mgreen text\x1b[0m", "een", "\x1b[32mgr<een\x1b[0m\x1b[32m text\x1b[0m", 1},
+	}
+
+	for _, s := range scenarios {
+		styled, count := StyleMatches(s.str, s.query, "<")
+		assert.EqualValues(t, s.expected, styled, s.str)
+		assert.EqualValues(t, s.expectedCount, count, s.str)
+	}
+}
+
+// TestFuzzyMatch is a function.
+func TestFuzzyMatch(t *testing.T) {
+	type scenario struct {
+		query    string
+		text     string
+		expected bool
+	}
+
+	scenarios := []scenario{
+		{"", "anything", true},
+		{"fbar", "feature/bar", true},
+		{"rbaf", "feature/bar", false},
+		{"feat bar", "feature/bar", true},
+		{"feat baz", "feature/bar", false},
+		{"FEAT", "feature/bar", false},
+		{"FEAT", "FEATURE/bar", true},
+		{"feat", "FEATURE/bar", true},
+		{"üb", "über", true},
+	}
+
+	for _, s := range scenarios {
+		assert.EqualValues(t, s.expected, FuzzyMatch(s.query, s.text), s.query+" in "+s.text)
+	}
+}